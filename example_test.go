@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/skelterjohn/go.matrix"
 	"math"
+	"math/cmplx"
+	"math/rand"
 )
 
 const (
@@ -55,6 +57,46 @@ func ExampleNDifferentiateBackwardThreePoint() {
 	// Output: 1.0000e+00
 }
 
+func ExampleNDifferentiateRichardson() {
+	var diff, _ = NDifferentiateRichardson(math.Exp, 1, 0.1)
+	fmt.Printf("%.12f\n", diff)
+	// Output: 2.718281828459
+}
+
+func ExampleNDifferentiateAuto() {
+	var diff = NDifferentiateAuto(math.Exp, 1)
+	fmt.Printf("%.12f\n", diff)
+	// Output: 2.718281828459
+}
+
+func ExampleNDifferentiateComplexStep() {
+	var f = func(z complex128) complex128 {
+		return cmplx.Exp(z) / cmplx.Sqrt(z)
+	}
+	var diff = NDifferentiateComplexStep(f, 1, 0)
+	fmt.Printf("%.15f\n", diff)
+	// Output: 1.359140914229523
+}
+
+func ExampleNFiniteDifferenceWeights() {
+	var weights = NFiniteDifferenceWeights(2, []float64{-1, 0, 1})
+	fmt.Println(weights)
+	// Output: [1 -2 1]
+}
+
+func ExampleNDifferentiateStencil() {
+	var diff = NDifferentiateStencil(math.Exp, 0, 0.01, 3,
+		[]float64{-2, -1, 0, 1, 2})
+	fmt.Printf("%.6f\n", diff)
+	// Output: 1.000025
+}
+
+func ExampleNDifferentiateHigher() {
+	var diff, _ = NDifferentiateHigher(math.Sin, 0, 3)
+	fmt.Printf("%.6f\n", diff)
+	// Output: -1.000000
+}
+
 func ExampleNIntegrateGaussKronrodNonAdaptive() {
 	var res, _ = NIntegrateGaussKronrodNonAdaptive(sinFunc, 0,
 		math.Pi, 0.001, 0.001)
@@ -62,6 +104,160 @@ func ExampleNIntegrateGaussKronrodNonAdaptive() {
 	// Output: 2.0000e+00
 }
 
+func ExampleNIntegrateGaussKronrod() {
+	var res, _ = NIntegrateGaussKronrod(math.Sqrt, 0, 1, 1e-8, 1e-8, 10000)
+	fmt.Printf("%.8f\n", res)
+	// Output: 0.66666667
+}
+
+func ExampleNIntegrateGaussKronrodDetailed() {
+	var res = NIntegrateGaussKronrodDetailed(math.Log, 0, 1, 1e-12, 1e-12,
+		&IntegrationOptions{MaxEvaluations: 300})
+	fmt.Printf("%.4f %v %v\n", res.Value, res.Status, res.Err() != nil)
+	// Output: -1.0000 max-evaluations true
+}
+
+func ExampleNIntegrateGaussKronrodParallel() {
+	var res = NIntegrateGaussKronrodParallel(math.Sqrt, 0, 1, 1e-10, 1e-10,
+		&IntegrationOptions{Workers: 4})
+	fmt.Printf("%.10f %v\n", res.Value, res.Status)
+	// Output: 0.6666666667 converged
+}
+
+func ExampleNIntegrateGaussKronrodBatch() {
+	var exp = func(x []float64, y []float64) {
+		for i := range x {
+			y[i] = math.Exp(x[i])
+		}
+	}
+	var res = NIntegrateGaussKronrodBatch(exp, 0, 1, 1e-10, 1e-10, nil)
+	fmt.Printf("%.10f %d\n", res.Value, res.Evaluations)
+	// Output: 1.7182818285 15
+}
+
+func ExampleNIntegrateInfinite() {
+	var gauss = func(x float64) float64 {
+		return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+	}
+	var res, _ = NIntegrateInfinite(gauss, math.Inf(-1), math.Inf(1),
+		1e-10, 1e-10, 0)
+	fmt.Printf("%.8f\n", res)
+	// Output: 1.00000000
+}
+
+func ExampleNIntegrateTanhSinh() {
+	var invSqrt = func(x float64) float64 {
+		return 1 / math.Sqrt(x)
+	}
+	var res, _ = NIntegrateTanhSinh(invSqrt, 0, 1, 1e-10, 1e-10)
+	fmt.Printf("%.8f\n", res)
+	// Output: 2.00000000
+}
+
+func ExampleNIntegrateRomberg() {
+	var res, _ = NIntegrateRomberg(math.Exp, 0, 1, 1e-12, 1e-12, 0)
+	fmt.Printf("%.10f\n", res)
+	// Output: 1.7182818285
+}
+
+func ExampleNIntegrateRombergTableau() {
+	var _, _, tableau = NIntegrateRombergTableau(sinFunc, 0, math.Pi,
+		0, 0, 3)
+	for _, row := range tableau {
+		for j, value := range row {
+			if j > 0 {
+				fmt.Print(" ")
+			}
+			fmt.Printf("%.6f", value)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// 0.000000
+	// 1.570796 2.094395
+	// 1.896119 2.004560 1.998571
+	// 1.974232 2.000269 1.999983 2.000006
+}
+
+func ExampleNIntegrateGaussLegendre() {
+	var res = NIntegrateGaussLegendre(math.Exp, 0, 1, 200)
+	fmt.Printf("%.12f\n", res)
+	// Output: 1.718281828459
+}
+
+func ExampleNIntegrateGaussHermite() {
+	// The expected value of X^2 for X ~ N(1, 2^2) is 1 + 4
+	var square = func(x float64) float64 {
+		var y = 1 + math.Sqrt2*2*x
+		return y * y / math.Sqrt(math.Pi)
+	}
+	var res = NIntegrateGaussHermite(square, 10)
+	fmt.Printf("%.4e\n", res)
+	// Output: 5.0000e+00
+}
+
+func ExampleNIntegrateClenshawCurtis() {
+	var res, _ = NIntegrateClenshawCurtis(math.Exp, -1, 1, 1e-12, 1e-12, 0)
+	fmt.Printf("%.10f\n", res)
+	// Output: 2.3504023873
+}
+
+func ExampleNIntegrateOscillatory() {
+	var res, _ = NIntegrateOscillatory(math.Exp, 0, 1, 1000, OscillatoryCos,
+		1e-12, 1e-8)
+	fmt.Printf("%.6e\n", res)
+	// Output: 2.248218e-03
+}
+
+func ExampleNIntegrateCauchy() {
+	// The principal value of the integral of 1/(x-1) in [0, 3] is ln(2)
+	var one = func(float64) float64 { return 1 }
+	var res, _ = NIntegrateCauchy(one, 0, 3, 1, 1e-10, 1e-10, 0)
+	fmt.Printf("%.8f\n", res)
+	// Output: 0.69314718
+}
+
+func ExampleNIntegrateExtrapolated() {
+	var res, _ = NIntegrateExtrapolated(math.Log, 0, 1, 1e-12, 1e-12, 0)
+	fmt.Printf("%.10f\n", res)
+	// Output: -1.0000000000
+}
+
+func ExampleNExtrapolateSequence() {
+	// Partial sums of 1 - 1/3 + 1/5 - ... converge slowly to pi/4
+	var terms []float64
+	var sum = 0.0
+	for k := 0; k < 10; k++ {
+		sum += math.Pow(-1, float64(k)) / float64(2*k+1)
+		terms = append(terms, sum)
+	}
+	var res, _ = NExtrapolateSequence(terms)
+	fmt.Printf("%.4f %.6f\n", sum*4, res*4)
+	// Output: 3.0418 3.141593
+}
+
+func ExampleNIntegrateContour() {
+	// Counts the zeros of z^3 - 1 inside the circle |z - 1| = 1 by the
+	// argument principle
+	var logDerivative = func(z complex128) complex128 {
+		return 3 * z * z / (z*z*z - 1)
+	}
+	var res, _ = NIntegrateContour(logDerivative, CircleContour(1, 1),
+		1e-8, 1e-8, 0)
+	fmt.Printf("%.4f\n", real(res/complex(0, 2*math.Pi)))
+	// Output: 1.0000
+}
+
+func ExampleNInverseLaplaceTalbot() {
+	// The step response of the transfer function 1/(s+1)
+	var step = func(s complex128) complex128 {
+		return 1 / (s * (s + 1))
+	}
+	var res = NInverseLaplaceTalbot(step, 2, 0)
+	fmt.Printf("%.10f\n", res)
+	// Output: 0.8646647168
+}
+
 func ExampleNIntegrateSimpsonAdaptive() {
 	var res, _ = NIntegrateSimpsonAdaptive(sinFunc, 0,
 		math.Pi/2, 0.001, 0.001)
@@ -69,6 +265,63 @@ func ExampleNIntegrateSimpsonAdaptive() {
 	// Output: 9.9999e-01
 }
 
+func ExampleNIntegrateCubature() {
+	var f = func(x []float64) float64 {
+		return math.Exp(-x[0]*x[0] - x[1]*x[1])
+	}
+	var res, _ = NIntegrateCubature(f, []float64{-1, -1}, []float64{1, 1},
+		1e-9, 1e-9, 0)
+	fmt.Printf("%.8f\n", res)
+	// Output: 2.23098514
+}
+
+func ExampleNIntegrateQuasiMonteCarlo() {
+	// The integral of |x|^2 over the unit cube in 10 dimensions is 10/3
+	var a, b = make([]float64, 10), make([]float64, 10)
+	for i := range b {
+		b[i] = 1
+	}
+	var squareNorm = func(x []float64) float64 {
+		var sum = 0.0
+		for _, xi := range x {
+			sum += xi * xi
+		}
+		return sum
+	}
+	var res, _ = NIntegrateQuasiMonteCarlo(squareNorm, a, b, 4096,
+		NewSobolSequence(10), rand.New(rand.NewSource(1)))
+	fmt.Printf("%.4f\n", res)
+	// Output: 3.3333
+}
+
+func ExampleNIntegrateTriangle() {
+	// The integral of x*y over the triangle (0, 0), (1, 0), (0, 1) is 1/24
+	var xy = func(x []float64) float64 {
+		return x[0] * x[1]
+	}
+	var res, _ = NIntegrateTriangle(xy, []float64{0, 0}, []float64{1, 0},
+		[]float64{0, 1}, 1e-10, 1e-10, 0)
+	fmt.Printf("%.6f\n", res*24)
+	// Output: 1.000000
+}
+
+func ExampleNIntegrateSamplesSimpson() {
+	var x = []float64{0, 0.2, 0.5, 0.9, 1.4, 2}
+	var y = make([]float64, len(x))
+	for i := range x {
+		y[i] = x[i] * x[i]
+	}
+	fmt.Printf("%.4e\n", NIntegrateSamplesSimpson(x, y))
+	// Output: 2.6667e+00
+}
+
+func ExampleNIntegrateSamplesCumulative() {
+	var x = []float64{0, 1, 3, 4}
+	var y = []float64{2, 2, 1, 0}
+	fmt.Println(NIntegrateSamplesCumulative(x, y))
+	// Output: [0 2 5 5.5]
+}
+
 func ExampleNSimpleSolveBisection() {
 	var res = NSimpleSolveBisection(sinFunc, 3*math.Pi/4, maxIterations,
 		defEpsilon)
@@ -118,3 +371,12 @@ func ExampleNSolveSystemFixedPoint() {
 	fmt.Printf("%.4e %.4e\n", res.Get(0, 0), res.Get(0, 1))
 	//Output: 6.4624e-01 6.0803e-01
 }
+
+func ExampleNSolveSystemNewtonAuto() {
+	var x0 matrix.Matrix = matrix.Zeros(1, 2)
+	x0.Set(0, 0, 0.6)
+	x0.Set(0, 1, 0.6)
+	var res = NSolveSystemNewtonAuto(test2d, x0, maxIterations, 1e-12)
+	fmt.Printf("%.4e %.4e\n", res.Get(0, 0), res.Get(0, 1))
+	// Output: 6.4628e-01 6.0811e-01
+}
@@ -0,0 +1,149 @@
+// numbatch.go
+package gonumeth
+
+import "math"
+
+// BatchFunction evaluates a function of one variable at several points in
+// one call, setting y[i] to the value at x[i]. The slices have the same
+// length. The function must not modify x or keep the slices after it
+// returns.
+type BatchFunction func(x []float64, y []float64)
+
+// VectorizeFunction turns f into a BatchFunction that evaluates the points
+// one after another, so scalar integrands can be used with the batch
+// integrators.
+func VectorizeFunction(f SingleVarFunction) BatchFunction {
+	return func(x []float64, y []float64) {
+		for i := range x {
+			y[i] = f(x[i])
+		}
+	}
+}
+
+// NIntegrateGaussKronrodBatch works the same way as
+// NIntegrateGaussKronrodDetailed, but takes a BatchFunction. All 30 nodes
+// of the two halves of a bisection are requested in one call, and the
+// first call requests the 15 nodes of [a, b]. The result is the same as
+// that of NIntegrateGaussKronrodDetailed for the same function.
+func NIntegrateGaussKronrodBatch(f BatchFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	evaluate := func(intervals []gkSubinterval) {
+		var (
+			x []float64 = make([]float64, 0, kronrodNodeCount*len(intervals))
+			y []float64 = make([]float64, kronrodNodeCount*len(intervals))
+		)
+		for _, s := range intervals {
+			center, halfLength := (s.a+s.b)/2, (s.b-s.a)/2
+			for i := 0; i < kronrodNodeCount; i++ {
+				x = append(x, center+kronrodNodes[i][0]*halfLength)
+			}
+		}
+		f(x, y)
+		for i := range intervals {
+			s := &intervals[i]
+			s.result, s.errorEstimate, s.absResult = gaussKronrodSums(
+				y[i*kronrodNodeCount:(i+1)*kronrodNodeCount], (s.b-s.a)/2)
+		}
+	}
+	detailed, _ = gaussKronrodAdaptive(evaluate, a, b, goalErrorAbs,
+		goalErrorRel, options.withDefaults(0, 0), 1)
+	return
+}
+
+// A subinterval of the breadth-first Simpson's method with the values of f
+// at its ends, midpoint and quarter points, and its Simpson estimate S.
+type simpsonPanel struct {
+	a  float64
+	b  float64
+	fa float64
+	fb float64
+	fc float64
+	fd float64
+	fe float64
+	S  float64
+}
+
+// NIntegrateSimpsonAdaptiveBatch works the same way as
+// NIntegrateSimpsonAdaptiveDetailed, but takes a BatchFunction. The
+// subintervals are refined breadth-first: all the subintervals of a depth
+// that miss the targets are bisected together, and the 4 new nodes of each
+// are requested in one call. The first call requests the 5 nodes of
+// [a, b], and a MaxEvaluations below 5 makes no call at all. The same
+// subintervals are accepted as by the recursive method, unless the
+// evaluation budget runs out, which here stops the deepest level instead
+// of the rightmost subintervals.
+func NIntegrateSimpsonAdaptiveBatch(f BatchFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	limits := options.withDefaults(simpsonDefaultMaxEvaluations,
+		simpsonDefaultMaxDepth)
+	if limits.MaxEvaluations < 5 {
+		// Not even the first estimate fits into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = IntegrationMaxEvaluations
+		return
+	}
+	c := (a + b) / 2
+	var (
+		x []float64 = []float64{a, b, c, (a + c) / 2, (c + b) / 2}
+		y []float64 = make([]float64, len(x))
+	)
+	f(x, y)
+	detailed.Evaluations = len(x)
+	pending := []simpsonPanel{{a: a, b: b, fa: y[0], fb: y[1], fc: y[2],
+		fd: y[3], fe: y[4], S: (b - a) / 6 * (y[0] + 4*y[2] + y[1])}}
+	for depth := 0; len(pending) > 0; depth++ {
+		goal := math.Ldexp(goalErrorAbs, -depth)
+		next := make([]simpsonPanel, 0, 2*len(pending))
+		x = make([]float64, 0, 4*len(pending))
+		for _, p := range pending {
+			c := (p.a + p.b) / 2
+			h := p.b - p.a
+			d := (p.a + c) / 2
+			e := (c + p.b) / 2
+			S_left := (h / 12) * (p.fa + 4*p.fd + p.fc)
+			S_right := (h / 12) * (p.fc + 4*p.fe + p.fb)
+			S2 := S_left + S_right
+			errorEstimate := (S2 - p.S) / 15
+			err := math.Abs(errorEstimate)
+			var status IntegrationStatus = IntegrationConverged
+			switch {
+			case err <= goal && err <= math.Abs(p.S)*goalErrorRel &&
+				err <= math.Abs(S2)*goalErrorRel:
+			case !isFinite(S2):
+				status = IntegrationDivergent
+			case !canBisectTwice(p.a, c) || !canBisectTwice(c, p.b):
+				status = IntegrationRoundoff
+			default:
+				var ok bool
+				ok, status = limits.allow(detailed.Evaluations+len(x), 4,
+					depth+1, h/2)
+				if !ok {
+					break
+				}
+				next = append(next,
+					simpsonPanel{a: p.a, b: c, fa: p.fa, fb: p.fc, fc: p.fd,
+						S: S_left},
+					simpsonPanel{a: c, b: p.b, fa: p.fc, fb: p.fb, fc: p.fe,
+						S: S_right})
+				x = append(x, (p.a+d)/2, (d+c)/2, (c+e)/2, (e+p.b)/2)
+				continue
+			}
+			detailed.Status = worseStatus(detailed.Status, status)
+			detailed.Subintervals++
+			detailed.Value += S2 + errorEstimate
+			detailed.ErrorEstimate += err
+		}
+		if len(x) > 0 {
+			y = make([]float64, len(x))
+			f(x, y)
+			detailed.Evaluations += len(x)
+			for i := range next {
+				next[i].fd, next[i].fe = y[2*i], y[2*i+1]
+			}
+		}
+		pending = next
+	}
+	return
+}
@@ -0,0 +1,66 @@
+// numbatch_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests that the batch Gauss-Kronrod method requests whole rules and
+// matches the scalar one
+func TestGaussKronrodBatchTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testIntegrals {
+		calls := 0
+		batch := VectorizeFunction(tt.f)
+		f := func(x []float64, y []float64) {
+			calls++
+			if len(x) != 15 && len(x) != 30 {
+				t.Error("Integral", i, "requested", len(x), "nodes")
+			}
+			batch(x, y)
+		}
+		detailed := NIntegrateGaussKronrodBatch(f, tt.a, tt.b, goal, goal,
+			nil)
+		scalar := NIntegrateGaussKronrodDetailed(tt.f, tt.a, tt.b, goal,
+			goal, nil)
+		if detailed != scalar {
+			t.Error("Integral", i, "gave", detailed, "instead of", scalar)
+		}
+		// Each bisection adds one subinterval and costs one call
+		if calls != detailed.Subintervals {
+			t.Error("Integral", i, "used", calls, "calls for",
+				detailed.Subintervals, "subintervals")
+		}
+	}
+}
+
+// Tests that the batch Simpson's method accepts the same subintervals as
+// the recursive one
+func TestSimpsonAdaptiveBatchTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testIntegrals {
+		calls := 0
+		batch := VectorizeFunction(tt.f)
+		f := func(x []float64, y []float64) {
+			calls++
+			batch(x, y)
+		}
+		detailed := NIntegrateSimpsonAdaptiveBatch(f, tt.a, tt.b, goal, goal,
+			nil)
+		scalar := NIntegrateSimpsonAdaptiveDetailed(tt.f, tt.a, tt.b, goal,
+			goal, nil)
+		if detailed.Subintervals != scalar.Subintervals ||
+			detailed.Evaluations != scalar.Evaluations ||
+			detailed.Status != scalar.Status {
+			t.Error("Integral", i, "gave", detailed, "instead of", scalar)
+		}
+		if math.Abs(detailed.Value-scalar.Value) > 1e-12 {
+			t.Error("Integral", i, "produced", detailed.Value, "instead of",
+				scalar.Value)
+		}
+		if calls > simpsonDefaultMaxDepth+1 {
+			t.Error("Integral", i, "used", calls, "calls")
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package gonumeth
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// ComplexFunction is a type used to represent a function of a complex
+// variable, such as an analytic function to be integrated along a contour.
+type ComplexFunction func(complex128) complex128
+
+// RealToComplexFunction is a type used to represent a complex valued
+// function of a real variable.
+type RealToComplexFunction func(float64) complex128
+
+// Applies the 7-point Gauss and the 15-point Kronrod rules to the complex
+// function f in [a, b], in the same way as gaussKronrodRule. The real and
+// imaginary parts are summed separately by gaussKronrodSums, and the error
+// estimate and the integral of |f| combine both.
+func gaussKronrodRuleComplex(f RealToComplexFunction, a float64,
+	b float64) (result float64, imagResult float64, errorEstimate float64,
+	absResult float64) {
+	var (
+		center     float64 = (a + b) / 2
+		halfLength float64 = (b - a) / 2
+		realValues [kronrodNodeCount]float64
+		imagValues [kronrodNodeCount]float64
+	)
+	for i := 0; i < kronrodNodeCount; i++ {
+		value := f(center + kronrodNodes[i][0]*halfLength)
+		realValues[i], imagValues[i] = real(value), imag(value)
+	}
+	result, realError, realAbs := gaussKronrodSums(realValues[:], halfLength)
+	imagResult, imagError, imagAbs := gaussKronrodSums(imagValues[:],
+		halfLength)
+	errorEstimate = math.Hypot(realError, imagError)
+	absResult = math.Hypot(realAbs, imagAbs)
+	return
+}
+
+// NIntegrateComplex works the same way as NIntegrateGaussKronrod for a
+// complex valued integrand. Both parts of the integral are found from the
+// same evaluations of f, and the error estimate bounds the modulus of the
+// error, so the relative target applies to |result|. As with
+// NIntegrateGaussKronrodDetailed, the bisection also stops at the rounding
+// level of the integral of |f|, so integrals whose value is zero terminate
+// without a budget.
+func NIntegrateComplex(f RealToComplexFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result complex128, errorEstimate float64) {
+	result, detailed := NIntegrateComplexDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return result, detailed.ErrorEstimate
+}
+
+// NIntegrateComplexDetailed works the same way as NIntegrateComplex, but
+// also returns an IntegrationResult and accepts options, with the limits
+// and statuses of NIntegrateGaussKronrodDetailed. The Value of the
+// IntegrationResult is the real part of result.
+func NIntegrateComplexDetailed(f RealToComplexFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (result complex128,
+	detailed IntegrationResult) {
+	evaluate := func(intervals []gkSubinterval) {
+		for i := range intervals {
+			s := &intervals[i]
+			s.result, s.imagResult, s.errorEstimate, s.absResult =
+				gaussKronrodRuleComplex(f, s.a, s.b)
+		}
+	}
+	detailed, imagValue := gaussKronrodAdaptive(evaluate, a, b, goalErrorAbs,
+		goalErrorRel, options.withDefaults(0, 0), 1)
+	return complex(detailed.Value, imagValue), detailed
+}
+
+// A smooth piece of a contour, parametrized by t in [0, 1], together with
+// the derivative of the parametrization.
+type contourPiece struct {
+	z  func(t float64) complex128
+	dz func(t float64) complex128
+}
+
+// ContourPath is a piecewise smooth path in the complex plane. Paths are
+// made by LineSegmentContour, CircleContour and PolylineContour, and are
+// integrated along by NIntegrateContour.
+type ContourPath struct {
+	pieces []contourPiece
+}
+
+// Returns the straight piece from z0 to z1
+func segmentPiece(z0 complex128, z1 complex128) contourPiece {
+	return contourPiece{
+		z: func(t float64) complex128 {
+			return z0 + complex(t, 0)*(z1-z0)
+		},
+		dz: func(float64) complex128 {
+			return z1 - z0
+		},
+	}
+}
+
+// LineSegmentContour returns the straight path from z0 to z1
+func LineSegmentContour(z0 complex128, z1 complex128) ContourPath {
+	return ContourPath{[]contourPiece{segmentPiece(z0, z1)}}
+}
+
+// PolylineContour returns the path through points, joined by straight
+// segments. For a closed path, repeat the first point at the end.
+func PolylineContour(points ...complex128) ContourPath {
+	var path ContourPath
+	for i := 1; i < len(points); i++ {
+		path.pieces = append(path.pieces, segmentPiece(points[i-1], points[i]))
+	}
+	return path
+}
+
+// CircleContour returns the circle with the given center and radius,
+// traversed once counterclockwise starting from center + radius.
+func CircleContour(center complex128, radius float64) ContourPath {
+	return ContourPath{[]contourPiece{{
+		z: func(t float64) complex128 {
+			return center + cmplx.Rect(radius, 2*math.Pi*t)
+		},
+		dz: func(t float64) complex128 {
+			return complex(0, 2*math.Pi) * cmplx.Rect(radius, 2*math.Pi*t)
+		},
+	}}}
+}
+
+// NIntegrateContour attempts to find the numeric value of the integral of
+// the complex function f along path. Every smooth piece of the path is
+// integrated by NIntegrateComplex over its parameter, each receiving an
+// equal share of goalErrorAbs and of maxEvaluations (0 still means no
+// limit). The error estimates of the pieces are added up.
+// By the argument principle, the integral of f'/f along a closed path,
+// divided by 2*pi*i, counts the zeros minus the poles of f inside it.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateContour(f ComplexFunction, path ContourPath,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result complex128, errorEstimate float64) {
+	result, detailed := NIntegrateContourDetailed(f, path, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return result, detailed.ErrorEstimate
+}
+
+// NIntegrateContourDetailed works the same way as NIntegrateContour, but
+// also returns an IntegrationResult and accepts options. Every piece
+// receives an equal share of MaxEvaluations, and MaxDepth and MinWidth as
+// they are, with MinWidth measured in the parameter of the piece. The
+// evaluations and subintervals of the pieces are added up, the status is
+// the worst of theirs, and the Value is the real part of result.
+func NIntegrateContourDetailed(f ComplexFunction, path ContourPath,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (result complex128,
+	detailed IntegrationResult) {
+	count := len(path.pieces)
+	if count == 0 {
+		return
+	}
+	pieceLimits := options.withDefaults(0, 0)
+	budget := pieceLimits.MaxEvaluations / count
+	if pieceLimits.MaxEvaluations != 0 && budget == 0 {
+		// A budget of 0 would mean no limit
+		budget = 1
+	}
+	pieceLimits.MaxEvaluations = budget
+	for _, piece := range path.pieces {
+		z, dz := piece.z, piece.dz
+		g := func(t float64) complex128 {
+			return f(z(t)) * dz(t)
+		}
+		pieceResult, pieceDetailed := NIntegrateComplexDetailed(g, 0, 1,
+			goalErrorAbs/float64(count), goalErrorRel, &pieceLimits)
+		result += pieceResult
+		detailed.ErrorEstimate += pieceDetailed.ErrorEstimate
+		detailed.Evaluations += pieceDetailed.Evaluations
+		detailed.Subintervals += pieceDetailed.Subintervals
+		detailed.Status = worseStatus(detailed.Status, pieceDetailed.Status)
+	}
+	detailed.Value = real(result)
+	return
+}
@@ -0,0 +1,60 @@
+// numcomplex_test.go
+package gonumeth
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Tests a complex integrand on a real interval
+func TestIntegrateComplexExponential(t *testing.T) {
+	f := func(x float64) complex128 {
+		return cmplx.Exp(complex(0, 3*x))
+	}
+	exact := (cmplx.Exp(complex(0, 3)) - 1) / complex(0, 3)
+	result, errorEstimate := NIntegrateComplex(f, 0, 1, 1e-12, 1e-12, 0)
+	if cmplx.Abs(result-exact) > 1e-12 {
+		t.Error("Produced", result, "with error estimate", errorEstimate,
+			"instead of", exact)
+	}
+	// The integral over a whole period is 0, so the relative target can not
+	// be met and the bisection must stop at the rounding level
+	calls := 0
+	period := func(x float64) complex128 {
+		calls++
+		return f(x)
+	}
+	result, _ = NIntegrateComplex(period, 0, 2*math.Pi/3, 1e-10, 1e-10, 0)
+	if cmplx.Abs(result) > 1e-12 || calls > 10000 {
+		t.Error("Produced", result, "with", calls, "evaluations instead of 0")
+	}
+}
+
+// Tests contour integrals along closed paths against the residue theorem
+func TestContourResidues(t *testing.T) {
+	inverse := func(z complex128) complex128 { return 1 / z }
+	square := PolylineContour(complex(-1, -1), complex(1, -1), complex(1, 1),
+		complex(-1, 1), complex(-1, -1))
+	paths := []ContourPath{CircleContour(0, 1), CircleContour(0.5, 2), square}
+	for i, path := range paths {
+		result, _ := NIntegrateContour(inverse, path, 1e-10, 1e-10, 0)
+		if cmplx.Abs(result-complex(0, 2*math.Pi)) > 1e-9 {
+			t.Error("Path", i, "produced", result, "instead of 2*pi*i")
+		}
+	}
+	// The pole at 3 is outside of the unit circle. The integral is 0, so
+	// the relative target can not be met, but no budget is needed either.
+	outside := func(z complex128) complex128 { return 1 / (z - 3) }
+	if result, _ := NIntegrateContour(outside, CircleContour(0, 1), 1e-10,
+		1e-10, 0); cmplx.Abs(result) > 1e-9 {
+		t.Error("Produced", result, "instead of 0")
+	}
+	// An open segment of an entire function: the integral of z^2 from 0 to
+	// 1+i is (1+i)^3 / 3
+	result, _ := NIntegrateContour(func(z complex128) complex128 { return z * z },
+		LineSegmentContour(0, complex(1, 1)), 1e-12, 1e-12, 0)
+	if cmplx.Abs(result-complex(-2.0/3, 2.0/3)) > 1e-12 {
+		t.Error("Produced", result, "instead of", complex(-2.0/3, 2.0/3))
+	}
+}
@@ -0,0 +1,273 @@
+package gonumeth
+
+import (
+	"container/heap"
+	"math"
+)
+
+// MultiVarScalarFunction is a type used to represent a real function of
+// several variables. Its argument holds one coordinate per variable.
+// The integrators in this package reuse the argument slice between calls,
+// so a function must not retain it.
+type MultiVarScalarFunction func([]float64) float64
+
+// Nodes of the Genz-Malik rule on [-1, 1]^n. The embedded degree 5 rule
+// shares all nodes, except for the corners, with the degree 7 rule.
+var (
+	genzMalikLambda2 float64 = math.Sqrt(9.0 / 70)
+	genzMalikLambda4 float64 = math.Sqrt(9.0 / 10)
+	genzMalikLambda5 float64 = math.Sqrt(9.0 / 19)
+)
+
+// A box of an adaptive cubature together with its partial result, error
+// estimate, the dimension along which it should be split next and the
+// number of halvings that produced it.
+type cubatureBox struct {
+	center        []float64
+	halfWidth     []float64
+	result        float64
+	errorEstimate float64
+	splitDim      int
+	depth         int
+}
+
+// A max-heap of boxes ordered by their error estimates.
+// It implements heap.Interface.
+type cubatureBoxHeap []cubatureBox
+
+func (h cubatureBoxHeap) Len() int { return len(h) }
+
+func (h cubatureBoxHeap) Less(i, j int) bool {
+	return h[i].errorEstimate > h[j].errorEstimate
+}
+
+func (h cubatureBoxHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *cubatureBoxHeap) Push(x interface{}) {
+	*h = append(*h, x.(cubatureBox))
+}
+
+func (h *cubatureBoxHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Sums the results and error estimates of all boxes.
+func (h cubatureBoxHeap) totals() (result float64, errorEstimate float64) {
+	for _, box := range h {
+		result += box.result
+		errorEstimate += box.errorEstimate
+	}
+	return
+}
+
+// Returns the number of evaluations made by the Genz-Malik rule in n
+// dimensions.
+func genzMalikEvaluations(n int) int {
+	return 1 + 4*n + 2*n*(n-1) + (1 << uint(n))
+}
+
+// Applies the degree 7 Genz-Malik rule to f on box and stores the result in
+// it. The difference to the embedded degree 5 rule is used as the error
+// estimate. The box is to be split along the dimension with the largest
+// fourth divided difference, where f varies the least smoothly.
+// The slice point is used as scratch space for the arguments of f.
+func genzMalikRule(f MultiVarScalarFunction, box *cubatureBox,
+	point []float64) {
+	var (
+		n       int     = len(box.center)
+		nf      float64 = float64(n)
+		volume  float64 = 1
+		f0      float64
+		sum2    float64 = 0
+		sum3    float64 = 0
+		sum4    float64 = 0
+		sum5    float64 = 0
+		maxDiff float64 = -1
+		ratio   float64 = genzMalikLambda2 * genzMalikLambda2 /
+			(genzMalikLambda4 * genzMalikLambda4)
+	)
+	for i := 0; i < n; i++ {
+		volume *= 2 * box.halfWidth[i]
+	}
+	copy(point, box.center)
+	f0 = f(point)
+	for i := 0; i < n; i++ {
+		c, h := box.center[i], box.halfWidth[i]
+		point[i] = c - genzMalikLambda2*h
+		f2 := f(point)
+		point[i] = c + genzMalikLambda2*h
+		f2 += f(point)
+		point[i] = c - genzMalikLambda4*h
+		f3 := f(point)
+		point[i] = c + genzMalikLambda4*h
+		f3 += f(point)
+		point[i] = c
+		sum2 += f2
+		sum3 += f3
+		diff := math.Abs(f2 - 2*f0 - ratio*(f3-2*f0))
+		if diff > maxDiff {
+			maxDiff = diff
+			box.splitDim = i
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			for _, si := range [2]float64{-1, 1} {
+				for _, sj := range [2]float64{-1, 1} {
+					point[i] = box.center[i] +
+						si*genzMalikLambda4*box.halfWidth[i]
+					point[j] = box.center[j] +
+						sj*genzMalikLambda4*box.halfWidth[j]
+					sum4 += f(point)
+				}
+			}
+			point[i], point[j] = box.center[i], box.center[j]
+		}
+	}
+	// Every corner of the scaled box, enumerated by the bits of k
+	for k := 0; k < 1<<uint(n); k++ {
+		for i := 0; i < n; i++ {
+			offset := genzMalikLambda5 * box.halfWidth[i]
+			if k&(1<<uint(i)) != 0 {
+				offset = -offset
+			}
+			point[i] = box.center[i] + offset
+		}
+		sum5 += f(point)
+	}
+	degree7 := (12824-9120*nf+400*nf*nf)/19683*f0 + 980.0/6561*sum2 +
+		(1820-400*nf)/19683*sum3 + 200.0/19683*sum4 +
+		6859.0/19683/float64(int(1)<<uint(n))*sum5
+	degree5 := (729-950*nf+50*nf*nf)/729*f0 + 245.0/486*sum2 +
+		(265-100*nf)/1458*sum3 + 25.0/729*sum4
+	box.result = volume * degree7
+	box.errorEstimate = volume * math.Abs(degree7-degree5)
+}
+
+// NIntegrateCubature attempts to find the numeric value of the integral of
+// f over the box [a[0], b[0]] x ... x [a[n-1], b[n-1]] adaptively.
+// Each box is integrated by the degree 7 Genz-Malik rule, with the embedded
+// degree 5 rule as error estimate, and the box with the largest error
+// estimate is repeatedly halved along the dimension where f is least
+// smooth. This goes on until the error is within both targets
+// (goalErrorAbs and goalErrorRel * |result|) or until maxEvaluations
+// function evaluations have been spent. A value of 0 for maxEvaluations
+// means there is no limit.
+// A single box costs 2^n + 2n^2 + 2n + 1 evaluations, so the method is
+// meant for moderate dimensions. For one dimension NIntegrateGaussKronrod is
+// used instead.
+// A `result` value of NaN means a and b are empty or differ in length.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateCubature(f MultiVarScalarFunction, a []float64, b []float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result float64, errorEstimate float64) {
+	detailed := NIntegrateCubatureDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateCubatureDetailed works the same way as NIntegrateCubature, but
+// returns an IntegrationResult and accepts options. The halving stops as
+// soon as splitting the worst box would break a limit, with the status of
+// that limit; by default there are no limits. MaxDepth counts the halvings
+// of a box and MinWidth is compared with the width of the halved side.
+// A box too narrow to be halved is reported as IntegrationRoundoff. The
+// first box costs 2^n + 2n^2 + 2n + 1 evaluations, so a smaller
+// MaxEvaluations evaluates nothing and returns a NaN value with an infinite
+// error estimate and IntegrationMaxEvaluations. For one dimension the
+// options are passed to NIntegrateGaussKronrodDetailed.
+// A Value of NaN with IntegrationDivergent means a and b are empty or
+// differ in length.
+func NIntegrateCubatureDetailed(f MultiVarScalarFunction, a []float64,
+	b []float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	n := len(a)
+	if n == 0 || n != len(b) {
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.NaN()
+		detailed.Status = IntegrationDivergent
+		return
+	}
+	point := make([]float64, n)
+	if n == 1 {
+		g := func(x float64) float64 {
+			point[0] = x
+			return f(point)
+		}
+		return NIntegrateGaussKronrodDetailed(g, a[0], b[0], goalErrorAbs,
+			goalErrorRel, options)
+	}
+	var (
+		limits IntegrationOptions = options.withDefaults(0, 0)
+		perBox int                = genzMalikEvaluations(n)
+	)
+	if ok, status := limits.allow(0, perBox, 0, math.Inf(1)); !ok {
+		// Not even the first box fits into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = status
+		return
+	}
+	initial := cubatureBox{center: make([]float64, n),
+		halfWidth: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		initial.center[i] = (a[i] + b[i]) / 2
+		initial.halfWidth[i] = (b[i] - a[i]) / 2
+	}
+	genzMalikRule(f, &initial, point)
+	var (
+		result        float64          = initial.result
+		errorEstimate float64          = initial.errorEstimate
+		boxes         *cubatureBoxHeap = &cubatureBoxHeap{initial}
+		status        IntegrationStatus
+	)
+	detailed.Evaluations = perBox
+	for {
+		if integrationGoalsMet(result, errorEstimate, goalErrorAbs,
+			goalErrorRel) {
+			break
+		}
+		if !isFinite(result) || !isFinite(errorEstimate) {
+			status = IntegrationDivergent
+			break
+		}
+		worst := heap.Pop(boxes).(cubatureBox)
+		d := worst.splitDim
+		halfWidth := worst.halfWidth[d] / 2
+		if worst.center[d]-halfWidth == worst.center[d] {
+			// The box can not be split any further
+			heap.Push(boxes, worst)
+			status = IntegrationRoundoff
+			break
+		}
+		var ok bool
+		if ok, status = limits.allow(detailed.Evaluations, 2*perBox,
+			worst.depth+1, 2*halfWidth); !ok {
+			heap.Push(boxes, worst)
+			break
+		}
+		for _, side := range [2]float64{-1, 1} {
+			child := cubatureBox{center: make([]float64, n),
+				halfWidth: make([]float64, n), depth: worst.depth + 1}
+			copy(child.center, worst.center)
+			copy(child.halfWidth, worst.halfWidth)
+			child.center[d] += side * halfWidth
+			child.halfWidth[d] = halfWidth
+			genzMalikRule(f, &child, point)
+			heap.Push(boxes, child)
+			result += child.result
+			errorEstimate += child.errorEstimate
+		}
+		detailed.Evaluations += 2 * perBox
+		result -= worst.result
+		errorEstimate -= worst.errorEstimate
+	}
+	// Summing afresh avoids the rounding accumulated by the updates above
+	detailed.Value, detailed.ErrorEstimate = boxes.totals()
+	detailed.Subintervals = boxes.Len()
+	detailed.Status = status
+	return
+}
@@ -0,0 +1,53 @@
+// numcubature_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns the box [0, 1]^n
+func unitBox(n int) (a []float64, b []float64) {
+	a, b = make([]float64, n), make([]float64, n)
+	for i := range b {
+		b[i] = 1
+	}
+	return
+}
+
+// Tests that a single Genz-Malik rule is exact for polynomials of degree 7
+func TestGenzMalikDegree(t *testing.T) {
+	f := func(x []float64) float64 {
+		return math.Pow(x[0], 4)*math.Pow(x[1], 3) + x[2]*x[2]
+	}
+	a, b := unitBox(3)
+	result, _ := NIntegrateCubature(f, a, b, 1, 1, 0)
+	if math.Abs(result-(1.0/20+1.0/3)) > 1e-14 {
+		t.Error("Produced", result, "for a polynomial of degree 7")
+	}
+}
+
+// Tests the adaptive cubature in several dimensions
+func TestCubatureGaussian(t *testing.T) {
+	f := func(x []float64) float64 {
+		sum := 0.0
+		for _, xi := range x {
+			sum += xi * xi
+		}
+		return math.Exp(-sum)
+	}
+	for n := 1; n <= 4; n++ {
+		a, b := unitBox(n)
+		result, errorEstimate := NIntegrateCubature(f, a, b, 1e-7, 1e-7, 0)
+		exact := math.Pow(math.Sqrt(math.Pi)/2*math.Erf(1), float64(n))
+		if math.Abs(result-exact) > 1e-7 || math.Abs(result-exact) >
+			errorEstimate {
+			t.Error("Dimension", n, "produced", result, "with error estimate",
+				errorEstimate, "instead of", exact)
+		}
+	}
+	if result, _ := NIntegrateCubature(f, []float64{0}, []float64{1, 1}, 1, 1,
+		0); !math.IsNaN(result) {
+		t.Error("Accepted bounds of different lengths")
+	}
+}
@@ -4,6 +4,10 @@
 // Type is assumed to be float64.
 package gonumeth
 
+import "math"
+
+// Weights of the fixed rules below. These are the exact rationals, which
+// NFiniteDifferenceWeights only reproduces up to roundoff for their stencils
 const (
 	d_c5_c_2 float64 = 1.0 / 12
 	d_c5_c_1 float64 = -2.0 / 3
@@ -102,7 +106,233 @@ func NDifferentiateBackwardThreePoint(f SingleVarFunction, x float64,
 	return
 }
 
-// Maybe TODO: implement n-point rules
+// Parameters of Ridders' method: the step is divided by riddersStepFactor
+// on each row of the tableau, and the refinement stops once the tableau
+// diverges from the best estimate by more than riddersSafety times its error.
+const (
+	riddersStepFactor float64 = 1.4
+	riddersMaxRows    int     = 10
+	riddersSafety     float64 = 2
+)
+
+// NDifferentiateRichardson takes a single variable function (f), a value (x)
+// and an initial step (h) and returns the numerical value of the derivative
+// of f at x along with an error estimate, using Ridders' method.
+// The 2-point central rule is applied with steps shrinking from h by a
+// constant factor, and each new value is extrapolated by Richardson's
+// method against the previous ones. The estimate with the smallest error
+// in the tableau is returned, and the refinement stops as soon as rounding
+// makes the error grow again. The initial h need not be small: the
+// function should only vary notably within h of x.
+// A result of NaN means h is zero or not finite.
+func NDifferentiateRichardson(f SingleVarFunction, x float64,
+	h float64) (result float64, errorEstimate float64) {
+	if h == 0 || !isFinite(h) {
+		return math.NaN(), math.NaN()
+	}
+	var (
+		factor2  float64 = riddersStepFactor * riddersStepFactor
+		previous []float64
+		current  []float64
+	)
+	errorEstimate = math.Inf(1)
+	for i := 0; i < riddersMaxRows; i++ {
+		current = make([]float64, i+1)
+		current[0] = (f(x+h) - f(x-h)) / (2 * h)
+		fac := factor2
+		for j := 1; j <= i; j++ {
+			// Eliminates the next power of h^2 from the previous column
+			current[j] = (current[j-1]*fac - previous[j-1]) / (fac - 1)
+			fac *= factor2
+			err := math.Max(math.Abs(current[j]-current[j-1]),
+				math.Abs(current[j]-previous[j-1]))
+			if err <= errorEstimate {
+				errorEstimate = err
+				result = current[j]
+			}
+		}
+		if i == 0 {
+			result = current[0]
+		} else if math.Abs(current[i]-previous[i-1]) >=
+			riddersSafety*errorEstimate {
+			break
+		}
+		previous = current
+		h /= riddersStepFactor
+	}
+	return
+}
+
+// The relative step of NDifferentiateComplexStep, small enough for its
+// truncation error to vanish in float64
+const complexStepDefault float64 = 1e-20
+
+// NDifferentiateComplexStep takes the complex extension of a real function
+// (f), a value (x) and a step (h) and returns the numerical value of the
+// derivative of f at x as Im(f(x+ih))/h. The extension must be analytic
+// near x and real on the real axis, as is the case when the formula of the
+// real function is written for complex128. No values are subtracted, so
+// the step can be tiny and the result is accurate to the float64
+// precision. A value of 0 for h selects 1e-20 * max(|x|, 1).
+func NDifferentiateComplexStep(f ComplexFunction, x float64,
+	h float64) (result float64) {
+	if h == 0 {
+		h = complexStepDefault * math.Max(math.Abs(x), 1)
+	}
+	return imag(f(complex(x, h))) / h
+}
+
+// NFiniteDifferenceWeights returns the weights of the finite-difference rule
+// for the derivative of the given order on a stencil of points x + s*h,
+// where s runs over offsets, by Fornberg's algorithm. The derivative is
+// approximated by the sum of weights[i] * f(x + offsets[i]*h) divided by
+// h^order. The rule has the highest possible order of accuracy for the
+// stencil, so the offsets {-2, -1, 1, 2} give the weights of
+// NDifferentiateCentral. The offsets must be distinct and more than order
+// of them are needed; otherwise nil is returned.
+func NFiniteDifferenceWeights(order int,
+	offsets []float64) (weights []float64) {
+	n := len(offsets)
+	if order < 0 || n <= order {
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if offsets[i] == offsets[j] {
+				return nil
+			}
+		}
+	}
+	// c[i][k] is the weight of point i for the k-th derivative on the
+	// points seen so far
+	c := make([][]float64, n)
+	for i := range c {
+		c[i] = make([]float64, order+1)
+	}
+	var (
+		c1 float64 = 1
+		c4 float64 = offsets[0]
+	)
+	c[0][0] = 1
+	for i := 1; i < n; i++ {
+		mn := i
+		if order < mn {
+			mn = order
+		}
+		c2, c5 := 1.0, c4
+		c4 = offsets[i]
+		for j := 0; j < i; j++ {
+			c3 := offsets[i] - offsets[j]
+			c2 *= c3
+			if j == i-1 {
+				for k := mn; k >= 1; k-- {
+					c[i][k] = c1 * (float64(k)*c[i-1][k-1] - c5*c[i-1][k]) /
+						c2
+				}
+				c[i][0] = -c1 * c5 * c[i-1][0] / c2
+			}
+			for k := mn; k >= 1; k-- {
+				c[j][k] = (c4*c[j][k] - float64(k)*c[j][k-1]) / c3
+			}
+			c[j][0] = c4 * c[j][0] / c3
+		}
+		c1 = c2
+	}
+	weights = make([]float64, n)
+	for i := range weights {
+		weights[i] = c[i][order]
+	}
+	return
+}
+
+// NDifferentiateStencil takes a single variable function (f), a value (x), a
+// step (h), the order of the derivative and the stencil offsets, and returns
+// the numerical value of the derivative of f at x by the rule of
+// NFiniteDifferenceWeights on the points x + offsets[i]*h. Note that an
+// appropriate value of h is essential, and that it grows with the order.
+// A result of NaN means the stencil is not valid for the order.
+func NDifferentiateStencil(f SingleVarFunction, x float64, h float64,
+	order int, offsets []float64) (result float64) {
+	weights := NFiniteDifferenceWeights(order, offsets)
+	if weights == nil {
+		return math.NaN()
+	}
+	for i, w := range weights {
+		if w != 0 {
+			result += w * f(x+offsets[i]*h)
+		}
+	}
+	return result / math.Pow(h, float64(order))
+}
+
+// Parameters of NDifferentiateHigher: the accuracy order of its stencils,
+// and the safety factor of its rounding error estimate, which also covers
+// the rounding of the abscissae and of f beyond eps * |f|
+const (
+	higherDerivativeAccuracy int     = 6
+	higherDerivativeSafety   float64 = 10
+)
+
+// NDifferentiateHigher returns the numerical value of the derivative of f
+// at x of the given order along with an error estimate. Unlike
+// NDerivativeHigher it does not nest first derivatives, but uses a single
+// central stencil of the smallest width that is accurate to the sixth
+// order, with weights from NFiniteDifferenceWeights. The step
+// eps^(1/(order+6)) * max(|x|, 1) balances the truncation error against
+// the rounding error, which grows as 1/h^order. The error is estimated by
+// comparing the result with the same stencil at twice the step, in the
+// manner of Richardson, plus the rounding error. The two stencils share
+// their points, so order 4, for example, costs 13 evaluations.
+// A result of NaN means the order is negative.
+func NDifferentiateHigher(f SingleVarFunction, x float64,
+	order int) (result float64, errorEstimate float64) {
+	if order < 0 {
+		return math.NaN(), math.NaN()
+	}
+	if order == 0 {
+		return f(x), 0
+	}
+	var (
+		m int     = (order+1)/2 + higherDerivativeAccuracy/2 - 1
+		h float64 = math.Pow(machineEpsilon,
+			1/float64(order+higherDerivativeAccuracy)) *
+			math.Max(math.Abs(x), 1)
+		offsets []float64       = make([]float64, 2*m+1)
+		values  map[int]float64 = make(map[int]float64)
+		fmax    float64
+	)
+	for i := range offsets {
+		offsets[i] = float64(i - m)
+	}
+	weights := NFiniteDifferenceWeights(order, offsets)
+	// Applies the stencil with the step scale*h, evaluating f on the grid
+	// of multiples of h
+	apply := func(scale int) (sum float64, weightSum float64) {
+		for i, w := range weights {
+			if w == 0 {
+				continue
+			}
+			k := scale * (i - m)
+			value, ok := values[k]
+			if !ok {
+				value = f(x + float64(k)*h)
+				values[k] = value
+				fmax = math.Max(fmax, math.Abs(value))
+			}
+			sum += w * value
+			weightSum += math.Abs(w)
+		}
+		return sum / math.Pow(float64(scale)*h, float64(order)),
+			weightSum
+	}
+	result, weightSum := apply(1)
+	coarse, _ := apply(2)
+	rounding := higherDerivativeSafety * weightSum * machineEpsilon * fmax /
+		math.Pow(h, float64(order))
+	errorEstimate = math.Abs(result-coarse)/
+		(math.Ldexp(1, higherDerivativeAccuracy)-1) + rounding
+	return
+}
 
 // NDerivative returns a function that approximates the original function's
 // derivative. The derivative is calculculated locally.
@@ -115,7 +345,9 @@ func NDerivative(f SingleVarFunction, h float64) (f_prime SingleVarFunction) {
 }
 
 // NDerivativeHigher returns the order-th derivative of f.
-// It is used just like NDerivative
+// It is used just like NDerivative. Each order nests NDifferentiateCentral,
+// so the cost grows as 4^order; NDifferentiateHigher is cheaper and more
+// accurate for orders above 2.
 func NDerivativeHigher(f SingleVarFunction, order int,
 	h float64) (f_ith SingleVarFunction) {
 	if order == 0 {
@@ -0,0 +1,177 @@
+// numdiff_test.go
+package gonumeth
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Functions with known derivatives, along with the point of evaluation
+var testDerivatives = []struct {
+	f     SingleVarFunction
+	x     float64
+	exact float64
+}{
+	{math.Sin, 1, math.Cos(1)},
+	{math.Exp, 2, math.Exp(2)},
+	{math.Log, 0.5, 2},
+	{func(x float64) float64 { return 1 / (1 + x*x) }, 0.5, -0.64},
+	{math.Sqrt, 1e-2, 5},
+}
+
+// Tests that Ridders' method is accurate for a coarse initial step, that
+// its error estimate bounds the actual error and that invalid steps fail
+func TestRichardsonTable(t *testing.T) {
+	for i, tt := range testDerivatives {
+		h := 0.1
+		if tt.x < 1 {
+			h = tt.x / 2
+		}
+		result, errorEstimate := NDifferentiateRichardson(tt.f, tt.x, h)
+		actual := math.Abs(result - tt.exact)
+		if actual > 1e-9*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Derivative", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		if actual > 10*errorEstimate+1e-15 {
+			t.Error("Derivative", i, "has error estimate", errorEstimate,
+				"far below the actual error", actual)
+		}
+	}
+	for _, h := range []float64{0, math.NaN(), math.Inf(1)} {
+		result, _ := NDifferentiateRichardson(math.Exp, 1, h)
+		if !math.IsNaN(result) {
+			t.Error("Step", h, "produced", result, "instead of NaN")
+		}
+	}
+}
+
+// Tests that the complex step is exact to the float64 precision, even for a
+// tiny step, where the real differences lose all digits
+func TestComplexStep(t *testing.T) {
+	tests := []struct {
+		f     ComplexFunction
+		x     float64
+		exact float64
+	}{
+		{cmplx.Sin, 1, math.Cos(1)},
+		{cmplx.Exp, 2, math.Exp(2)},
+		{cmplx.Log, 0.5, 2},
+		{func(z complex128) complex128 { return 1 / (1 + z*z) }, 0.5, -0.64},
+		{func(z complex128) complex128 { return z * z * z }, 1e5, 3e10},
+	}
+	for i, tt := range tests {
+		for _, h := range []float64{0, 1e-100} {
+			result := NDifferentiateComplexStep(tt.f, tt.x, h)
+			if math.Abs(result-tt.exact) > 4e-16*math.Abs(tt.exact) {
+				t.Error("Derivative", i, "with step", h, "produced", result,
+					"instead of", tt.exact)
+			}
+		}
+	}
+}
+
+// Tests that the generated weights reproduce the rules hard-coded in this
+// package, and that the hard-coded central rules are exactly antisymmetric
+func TestFiniteDifferenceWeightsConstants(t *testing.T) {
+	tests := []struct {
+		offsets []float64
+		weights []float64
+	}{
+		{[]float64{-2, -1, 1, 2}, []float64{d_c5_c_2, d_c5_c_1, d_c5_c1,
+			d_c5_c2}},
+		{[]float64{0, 1, 2, 3}, []float64{d_f5_c0, d_f5_c1, d_f5_c2,
+			d_f5_c3}},
+		{[]float64{0, -1, -2, -3}, []float64{d_b5_c0, d_b5_c_1, d_b5_c_2,
+			d_b5_c_3}},
+		{[]float64{-1, 1}, []float64{d_c3_c_1, d_c3_c1}},
+		{[]float64{0, 1, 2}, []float64{d_f3_c0, d_f3_c1, d_f3_c2}},
+		{[]float64{0, -1, -2}, []float64{d_b3_c0, d_b3_c_1, d_b3_c_2}},
+	}
+	for i, tt := range tests {
+		weights := NFiniteDifferenceWeights(1, tt.offsets)
+		for j := range weights {
+			if math.Abs(weights[j]-tt.weights[j]) > 1e-15 {
+				t.Error("Rule", i, "has weights", weights, "instead of",
+					tt.weights)
+				break
+			}
+		}
+	}
+	if d_c5_c_2 != -d_c5_c2 || d_c5_c_1 != -d_c5_c1 || d_c3_c_1 != -d_c3_c1 {
+		t.Error("Central weights are not antisymmetric")
+	}
+}
+
+// Tests higher orders against known rules and invalid stencils
+func TestFiniteDifferenceWeights(t *testing.T) {
+	second := NFiniteDifferenceWeights(2, []float64{-1, 0, 1})
+	if second[0] != 1 || second[1] != -2 || second[2] != 1 {
+		t.Error("Second derivative weights", second)
+	}
+	fourth := NFiniteDifferenceWeights(4, []float64{-2, -1, 0, 1, 2})
+	for j, w := range []float64{1, -4, 6, -4, 1} {
+		if math.Abs(fourth[j]-w) > 1e-13 {
+			t.Error("Fourth derivative weights", fourth)
+			break
+		}
+	}
+	if NFiniteDifferenceWeights(2, []float64{0, 1}) != nil ||
+		NFiniteDifferenceWeights(1, []float64{0, 1, 1}) != nil ||
+		NFiniteDifferenceWeights(-1, []float64{0, 1}) != nil {
+		t.Error("Accepted an invalid stencil")
+	}
+	// A non-uniform stencil is exact for polynomials of its degree
+	cubic := func(x float64) float64 { return x*x*x - 2*x }
+	result := NDifferentiateStencil(cubic, 1, 0.5, 2,
+		[]float64{-1, 0.5, 2, 3.5})
+	if math.Abs(result-6) > 1e-12 {
+		t.Error("Produced", result, "instead of 6")
+	}
+	if !math.IsNaN(NDifferentiateStencil(cubic, 1, 0.5, 3,
+		[]float64{0, 1})) {
+		t.Error("Produced a result for an invalid stencil")
+	}
+}
+
+// Tests the higher derivatives against their exact values and that the
+// error estimate bounds the actual error
+func TestDifferentiateHigher(t *testing.T) {
+	tests := []struct {
+		f     SingleVarFunction
+		x     float64
+		order int
+		exact float64
+	}{
+		{math.Exp, 1, 3, math.E},
+		{math.Exp, 1, 4, math.E},
+		{math.Sin, 0.5, 3, -math.Cos(0.5)},
+		{math.Sin, 0.5, 4, math.Sin(0.5)},
+		{math.Log, 2, 3, 0.25},
+		{math.Log, 2, 4, -0.375},
+		{func(x float64) float64 { return x * x * x * x * x }, 10, 5, 120},
+		{math.Cos, 0, 2, -1},
+		{math.Cos, 0, 1, 0},
+	}
+	for i, tt := range tests {
+		result, errorEstimate := NDifferentiateHigher(tt.f, tt.x, tt.order)
+		actual := math.Abs(result - tt.exact)
+		if actual > 1e-6*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Derivative", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		if actual > errorEstimate {
+			t.Error("Derivative", i, "has error estimate", errorEstimate,
+				"below the actual error", actual)
+		}
+		if errorEstimate > 1e-5*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Derivative", i, "has a loose error estimate",
+				errorEstimate)
+		}
+	}
+	if result, _ := NDifferentiateHigher(math.Exp, 1, -1); !math.IsNaN(
+		result) {
+		t.Error("Produced", result, "for a negative order")
+	}
+}
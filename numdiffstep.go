@@ -0,0 +1,174 @@
+// numdiffstep.go
+package gonumeth
+
+import "math"
+
+// The spacing of float64 numbers next to 1
+const machineEpsilon float64 = 2.220446049250313e-16
+
+// Parameters of the step selection. The pilot step is rescaled by
+// optimalStepPilotFactor while its difference is not finite or drowned in
+// rounding, and a pilot difference counts as resolved once it exceeds
+// optimalStepResolution times its own rounding error.
+const (
+	optimalStepIterations  int     = 8
+	optimalStepPilotFactor float64 = 10
+	optimalStepResolution  float64 = 100
+)
+
+// NDifferentiator computes the derivative of f at x, choosing any step it
+// needs by itself. NDifferentiateAuto is an NDifferentiator.
+type NDifferentiator func(f SingleVarFunction, x float64) float64
+
+// Describes a finite-difference rule for the step selection: its order of
+// accuracy p, the constant c of its truncation error c * h^p * |f^(p+m)|,
+// the sum of the absolute values of its weights, which scales the rounding
+// error, the direction of its points (0 for central, 1 for forward and
+// -1 for backward) and the order m of the derivative it approximates.
+type differenceRule struct {
+	order      int
+	truncation float64
+	weightSum  float64
+	direction  int
+	derivative int
+}
+
+// The rules of NDifferentiateCentral, NDifferentiateForward and
+// NDifferentiateBackward, and the 5-point central rule for the second
+// derivative on the stencil secondDifferenceOffsets
+var (
+	centralDifferenceRule   = differenceRule{4, 1.0 / 30, 3.0 / 2, 0, 1}
+	forwardDifferenceRule   = differenceRule{3, 1.0 / 4, 20.0 / 3, 1, 1}
+	backwardDifferenceRule  = differenceRule{3, 1.0 / 4, 20.0 / 3, -1, 1}
+	secondDifferenceRule    = differenceRule{4, 1.0 / 90, 16.0 / 3, 0, 2}
+	secondDifferenceOffsets = []float64{-2, -1, 0, 1, 2}
+)
+
+// Estimates |f^(k)| at x by a difference of order k with the given pilot
+// step, on the side of x given by direction, along with the rounding error
+// eps * |f| of the values used. It fails if a value is not finite or the
+// difference does not stand out from its rounding error.
+func pilotDerivative(f SingleVarFunction, x float64, k int, direction int,
+	pilot float64) (derivative float64, noise float64, finite bool,
+	resolved bool) {
+	var (
+		diff  float64 = 0
+		fmax  float64 = 0
+		binom float64 = 1
+	)
+	for j := 0; j <= k; j++ {
+		offset := float64(direction * j)
+		if direction == 0 {
+			offset = float64(j) - float64(k)/2
+		}
+		fj := f(x + offset*pilot)
+		if !isFinite(fj) {
+			return 0, 0, false, false
+		}
+		if (k-j)%2 == 0 {
+			diff += binom * fj
+		} else {
+			diff -= binom * fj
+		}
+		fmax = math.Max(fmax, math.Abs(fj))
+		binom = binom * float64(k-j) / float64(j+1)
+	}
+	noise = machineEpsilon * fmax
+	// The rounding error of the difference is about 2^k * noise
+	resolved = math.Abs(diff) > optimalStepResolution*math.Ldexp(noise, k)
+	derivative = math.Abs(diff) / math.Pow(pilot, float64(k))
+	return derivative, noise, true, resolved
+}
+
+// Chooses the step of rule for f at x that balances the truncation error
+// c * h^p * M against the rounding error weightSum * eps * |f| / h^m, which
+// is minimal for h = (m * weightSum * eps * |f| / (p * c * M))^(1/(p+m)).
+// The derivative M = |f^(p+m)| is estimated by pilot differences on the
+// same side of x as the rule, with a pilot step kept at a fixed multiple of
+// the chosen step until the two agree. If M can not be estimated, as for
+// polynomials of low degree, the rule of thumb eps^(1/(p+m)) * max(|x|, 1)
+// is returned.
+func optimalStep(f SingleVarFunction, x float64,
+	rule differenceRule) (h float64) {
+	var (
+		k     int     = rule.order + rule.derivative
+		scale float64 = math.Max(math.Abs(x), 1)
+		// The pilot difference has two orders more to balance
+		ratio float64 = math.Pow(machineEpsilon, 1/float64(k+2)-
+			1/float64(k))
+		pilot float64
+	)
+	h = math.Pow(machineEpsilon, 1/float64(k)) * scale
+	pilot = ratio * h
+	for i := 0; i < optimalStepIterations; i++ {
+		derivative, noise, finite, resolved := pilotDerivative(f, x, k,
+			rule.direction, pilot)
+		if !finite {
+			pilot /= optimalStepPilotFactor
+			continue
+		}
+		if !resolved {
+			pilot *= optimalStepPilotFactor
+			continue
+		}
+		h = math.Pow(float64(rule.derivative)*rule.weightSum*noise/
+			(float64(rule.order)*rule.truncation*derivative), 1/float64(k))
+		if math.Abs(ratio*h-pilot) <= pilot/2 {
+			break
+		}
+		pilot = ratio * h
+	}
+	return
+}
+
+// NOptimalStepCentral returns the step for NDifferentiateCentral at x that
+// balances the truncation error of the rule against the rounding error of
+// f, which is assumed to be accurate to the float64 precision. The fifth
+// derivative of f is estimated by pilot differences of 6 evaluations each,
+// of which a few are usually needed.
+func NOptimalStepCentral(f SingleVarFunction, x float64) (h float64) {
+	return optimalStep(f, x, centralDifferenceRule)
+}
+
+// NOptimalStepForward works the same way as NOptimalStepCentral, but for
+// NDifferentiateForward, and only evaluates f at x and to its right.
+func NOptimalStepForward(f SingleVarFunction, x float64) (h float64) {
+	return optimalStep(f, x, forwardDifferenceRule)
+}
+
+// NOptimalStepBackward works the same way as NOptimalStepCentral, but for
+// NDifferentiateBackward, and only evaluates f at x and to its left.
+func NOptimalStepBackward(f SingleVarFunction, x float64) (h float64) {
+	return optimalStep(f, x, backwardDifferenceRule)
+}
+
+// NDifferentiateAuto returns the numerical value of the derivative of f at
+// x by NDifferentiateCentral with the step chosen by NOptimalStepCentral.
+func NDifferentiateAuto(f SingleVarFunction, x float64) (result float64) {
+	return NDifferentiateCentral(f, x, NOptimalStepCentral(f, x))
+}
+
+// NDifferentiateAutoForward returns the numerical value of the derivative
+// of f at x by NDifferentiateForward with the step chosen by
+// NOptimalStepForward. It is suitable for functions undefined for y<x.
+func NDifferentiateAutoForward(f SingleVarFunction,
+	x float64) (result float64) {
+	return NDifferentiateForward(f, x, NOptimalStepForward(f, x))
+}
+
+// NDifferentiateAutoBackward returns the numerical value of the derivative
+// of f at x by NDifferentiateBackward with the step chosen by
+// NOptimalStepBackward. It is suitable for functions undefined for y>x.
+func NDifferentiateAutoBackward(f SingleVarFunction,
+	x float64) (result float64) {
+	return NDifferentiateBackward(f, x, NOptimalStepBackward(f, x))
+}
+
+// Returns the numerical value of the second derivative of f at x by the
+// 5-point central rule, with the step chosen for it by optimalStep. Unlike
+// nesting NDifferentiateAuto, this differentiates f itself, so the rounding
+// model of the step selection holds. It is an NDifferentiator.
+func autoSecondDerivative(f SingleVarFunction, x float64) (result float64) {
+	return NDifferentiateStencil(f, x, optimalStep(f, x,
+		secondDifferenceRule), 2, secondDifferenceOffsets)
+}
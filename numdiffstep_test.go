@@ -0,0 +1,78 @@
+// numdiffstep_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests that the automatic steps give derivatives close to the float64
+// precision
+func TestDifferentiateAutoTable(t *testing.T) {
+	for i, tt := range testDerivatives {
+		tolerance := 1e-9 * math.Max(1, math.Abs(tt.exact))
+		if result := NDifferentiateAuto(tt.f, tt.x); math.Abs(result-
+			tt.exact) > tolerance {
+			t.Error("Derivative", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		if result := NDifferentiateAutoForward(tt.f, tt.x); math.Abs(result-
+			tt.exact) > 100*tolerance {
+			t.Error("Forward derivative", i, "produced", result,
+				"instead of", tt.exact)
+		}
+	}
+}
+
+// Tests that the step follows the scale of the function
+func TestOptimalStepScale(t *testing.T) {
+	for _, scale := range []float64{1e-3, 1, 1e3} {
+		f := func(x float64) float64 { return math.Exp(x / scale) }
+		h := NOptimalStepCentral(f, 0)
+		// The truncation and rounding errors balance at about
+		// eps^(1/5) times the scale
+		if h < scale*1e-4 || h > scale*1e-2 {
+			t.Error("Step", h, "for scale", scale)
+		}
+		result := NDifferentiateCentral(f, 0, h)
+		if math.Abs(result*scale-1) > 1e-11 {
+			t.Error("Produced", result, "instead of", 1/scale)
+		}
+	}
+	// Polynomials of low degree have no truncation error
+	h := NOptimalStepBackward(func(x float64) float64 { return x * x }, 2)
+	if h <= 0 || math.IsInf(h, 0) || math.IsNaN(h) {
+		t.Error("Step", h, "for a parabola")
+	}
+}
+
+// Tests that the second derivative is taken from f with a single chosen
+// step, accurately and at a cost that does not grow with nesting
+func TestAutoSecondDerivative(t *testing.T) {
+	tests := []struct {
+		f     SingleVarFunction
+		x     float64
+		exact float64
+	}{
+		{math.Sin, 1, -math.Sin(1)},
+		{math.Exp, 2, math.Exp(2)},
+		{math.Log, 0.5, -4},
+		{func(x float64) float64 { return math.Sin(1000 * x) }, 1e-3,
+			-1e6 * math.Sin(1)},
+	}
+	for i, tt := range tests {
+		calls := 0
+		f := func(x float64) float64 {
+			calls++
+			return tt.f(x)
+		}
+		result := autoSecondDerivative(f, tt.x)
+		if math.Abs(result-tt.exact) > 1e-7*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Second derivative", i, "produced", result,
+				"instead of", tt.exact)
+		}
+		if calls > 5+7*optimalStepIterations {
+			t.Error("Second derivative", i, "used", calls, "evaluations")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gonumeth
+
+import "math"
+
+// WynnEpsilon accelerates the convergence of a sequence by Wynn's epsilon
+// algorithm, which computes the Shanks transformations of the sequence.
+// It is exact for sequences whose error is a sum of a few geometric terms,
+// and works well for partial sums of alternating series, iterates of
+// linearly converging methods and the results of successive bisections
+// of an integral with an endpoint singularity.
+// Terms are added one at a time. The zero value is ready to use.
+type WynnEpsilon struct {
+	terms     []float64
+	estimates []float64
+}
+
+// Computes the best estimate of the limit from all terms. This is the entry
+// of the highest even column of the epsilon table that depends on the last
+// term. If the table breaks down because two entries are equal, the
+// sequence has converged, and the last entry of the column before is used.
+func (w *WynnEpsilon) limit() float64 {
+	n := len(w.terms)
+	var (
+		previous []float64 = make([]float64, n+1)
+		current  []float64 = append([]float64(nil), w.terms...)
+		best     float64   = w.terms[n-1]
+	)
+	for k := 1; k < n; k++ {
+		next := make([]float64, n-k)
+		for j := range next {
+			diff := current[j+1] - current[j]
+			if diff == 0 {
+				if k%2 == 1 {
+					// The column k-1 holds estimates
+					return current[n-k]
+				}
+				return best
+			}
+			next[j] = previous[j+1] + 1/diff
+			if math.IsInf(next[j], 0) || math.IsNaN(next[j]) {
+				return best
+			}
+		}
+		previous, current = current, next
+		if k%2 == 0 {
+			best = current[n-1-k]
+		}
+	}
+	return best
+}
+
+// Add appends term to the sequence and returns the extrapolated limit
+// together with an error estimate. The error is estimated from how much the
+// last three limits differ, so it is +Inf until three terms have been
+// added.
+func (w *WynnEpsilon) Add(term float64) (result float64,
+	errorEstimate float64) {
+	w.terms = append(w.terms, term)
+	result = w.limit()
+	w.estimates = append(w.estimates, result)
+	n := len(w.estimates)
+	if n < 3 {
+		return result, math.Inf(1)
+	}
+	errorEstimate = math.Abs(result-w.estimates[n-2]) +
+		math.Abs(result-w.estimates[n-3])
+	return
+}
+
+// NExtrapolateSequence returns the limit of the sequence terms as
+// extrapolated by Wynn's epsilon algorithm, together with an error
+// estimate. See WynnEpsilon for the kind of sequences it accelerates.
+// Vector sequences, such as the iterates of NSolveSystemFixedPoint, can be
+// extrapolated one component at a time.
+// A `result` value of NaN means terms is empty.
+func NExtrapolateSequence(terms []float64) (result float64,
+	errorEstimate float64) {
+	if len(terms) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	var w WynnEpsilon
+	for _, term := range terms {
+		result, errorEstimate = w.Add(term)
+	}
+	return
+}
@@ -0,0 +1,32 @@
+// numextrapolate_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests the acceleration of the alternating series for ln(2) and of a
+// geometric sequence, for which the algorithm is exact
+func TestExtrapolateSequence(t *testing.T) {
+	var (
+		terms []float64
+		sum   float64
+	)
+	for k := 1; k <= 12; k++ {
+		sum += math.Pow(-1, float64(k+1)) / float64(k)
+		terms = append(terms, sum)
+	}
+	result, errorEstimate := NExtrapolateSequence(terms)
+	if math.Abs(result-math.Ln2) > 1e-8 ||
+		math.Abs(result-math.Ln2) > errorEstimate {
+		t.Error("Produced", result, "with error estimate", errorEstimate,
+			"instead of", math.Ln2)
+	}
+	if result, _ := NExtrapolateSequence([]float64{1, 1.5, 1.75}); result != 2 {
+		t.Error("Produced", result, "instead of 2 for a geometric sequence")
+	}
+	if result, _ := NExtrapolateSequence(nil); !math.IsNaN(result) {
+		t.Error("Produced", result, "for an empty sequence")
+	}
+}
@@ -0,0 +1,404 @@
+package gonumeth
+
+import (
+	"math"
+	"sync"
+)
+
+// Maximum number of Newton steps taken when refining a node and of QL
+// sweeps spent on a single eigenvalue
+const (
+	gaussMaxNewtonSteps int = 100
+	gaussMaxQLSweeps    int = 60
+)
+
+// A dedicated type for the families of Gaussian quadrature rules
+type gaussFamily int16
+
+const (
+	gaussLegendre gaussFamily = iota
+	gaussLaguerre
+	gaussHermite
+	gaussJacobi
+)
+
+// Identifies a Gaussian rule in the node cache. The parameters alpha and
+// beta are only used by the Jacobi family.
+type gaussRuleKey struct {
+	family gaussFamily
+	n      int
+	alpha  float64
+	beta   float64
+}
+
+// Computed node sets are kept here, since generating them takes O(n^2)
+// operations while applying them takes only n evaluations.
+var (
+	gaussCacheLock sync.Mutex
+	gaussCache     = make(map[gaussRuleKey][][2]float64)
+)
+
+// Returns the cached rule for key, generating it with generate on a miss.
+// The result is shared and must not be modified.
+func cachedGaussRule(key gaussRuleKey,
+	generate func() [][2]float64) [][2]float64 {
+	gaussCacheLock.Lock()
+	defer gaussCacheLock.Unlock()
+	if rule, ok := gaussCache[key]; ok {
+		return rule
+	}
+	rule := generate()
+	gaussCache[key] = rule
+	return rule
+}
+
+// Evaluates the Legendre polynomial P_n and its derivative at x by the
+// three-term recurrence.
+func legendrePolynomial(n int, x float64) (p float64, dp float64) {
+	var p_1 float64 = 0
+	p = 1
+	for j := 1; j <= n; j++ {
+		p, p_1 = ((2*float64(j)-1)*x*p-(float64(j)-1)*p_1)/float64(j), p
+	}
+	dp = float64(n) * (x*p - p_1) / (x*x - 1)
+	return
+}
+
+// Computes the n-point Gauss-Legendre rule on [-1, 1]. Every root of P_n is
+// found by Newton's method, starting from an asymptotic approximation.
+// The iteration stops once the step is at the float64 resolution of the
+// root, or once it stops shrinking because P_n is lost in rounding.
+// The nodes come in symmetric pairs, so only half of them are searched for.
+func generateGaussLegendre(n int) (rule [][2]float64) {
+	rule = make([][2]float64, n)
+	for i := 0; i < (n+1)/2; i++ {
+		var (
+			x float64 = math.Cos(math.Pi * (float64(i) + 0.75) /
+				(float64(n) + 0.5))
+			dp       float64
+			previous float64 = math.Inf(1)
+		)
+		for step := 0; step < gaussMaxNewtonSteps; step++ {
+			var p float64
+			p, dp = legendrePolynomial(n, x)
+			dx := math.Abs(p / dp)
+			x -= p / dp
+			if dx <= machineEpsilon*math.Abs(x) || dx >= previous {
+				break
+			}
+			previous = dx
+		}
+		_, dp = legendrePolynomial(n, x)
+		weight := 2 / ((1 - x*x) * dp * dp)
+		rule[i] = [2]float64{-x, weight}
+		rule[n-1-i] = [2]float64{x, weight}
+	}
+	if n%2 == 1 {
+		// The middle node is exactly 0
+		rule[n/2][0] = 0
+	}
+	return
+}
+
+// GaussLegendreNodes returns the nodes and weights of the n-point
+// Gauss-Legendre rule on [-1, 1], in the same {node, weight} layout as the
+// built-in Gauss-Kronrod tables. The rule integrates polynomials of degree
+// up to 2n-1 exactly. Rules are computed once per n and then cached.
+// A value of nil is returned for n < 1.
+func GaussLegendreNodes(n int) (rule [][2]float64) {
+	if n < 1 {
+		return nil
+	}
+	return copyGaussRule(cachedGaussRule(gaussRuleKey{family: gaussLegendre,
+		n: n}, func() [][2]float64 {
+		return generateGaussLegendre(n)
+	}))
+}
+
+// Applies a rule on [-1, 1] to f in [a, b]
+func applyGaussRule(f SingleVarFunction, a float64, b float64,
+	rule [][2]float64) (result float64) {
+	center, halfLength := (a+b)/2, (b-a)/2
+	for _, node := range rule {
+		result += node[1] * f(center+halfLength*node[0])
+	}
+	return result * halfLength
+}
+
+// NIntegrateGaussLegendre finds the numeric value of the integral of f in
+// the interval [a, b] using the n-point Gauss-Legendre rule.
+// This is a fixed rule, so no error estimate is available. Comparing the
+// results for two different values of n gives a rough one.
+// Nodes for every n are generated on first use and cached, so repeated
+// calls only cost n function evaluations.
+// A `result` value of NaN means n < 1.
+func NIntegrateGaussLegendre(f SingleVarFunction, a float64, b float64,
+	n int) (result float64) {
+	if n < 1 {
+		return math.NaN()
+	}
+	return applyGaussRule(f, a, b, cachedGaussRule(
+		gaussRuleKey{family: gaussLegendre, n: n}, func() [][2]float64 {
+			return generateGaussLegendre(n)
+		}))
+}
+
+// Finds the eigenvalues of the symmetric tridiagonal matrix with diagonal d
+// and subdiagonal e (e[i] couples rows i and i+1) by the implicit QL method.
+// On return d holds the eigenvalues and z the first components of the
+// corresponding normalized eigenvectors. Only these components are needed
+// for the Golub-Welsch algorithm, so the rest of the eigenvectors are never
+// formed. The return value is false if the iteration did not converge.
+func tridiagonalEigen(d []float64, e []float64) (z []float64, ok bool) {
+	n := len(d)
+	z = make([]float64, n)
+	z[0] = 1
+	sub := make([]float64, n)
+	copy(sub, e)
+	for l := 0; l < n; l++ {
+		for sweep := 0; ; sweep++ {
+			m := l
+			for ; m < n-1; m++ {
+				dd := math.Abs(d[m]) + math.Abs(d[m+1])
+				if math.Abs(sub[m]) <= 1e-17*dd {
+					break
+				}
+			}
+			if m == l {
+				break
+			}
+			if sweep == gaussMaxQLSweeps {
+				return z, false
+			}
+			g := (d[l+1] - d[l]) / (2 * sub[l])
+			r := math.Hypot(g, 1)
+			g = d[m] - d[l] + sub[l]/(g+math.Copysign(r, g))
+			var (
+				s float64 = 1
+				c float64 = 1
+				p float64 = 0
+				i int
+			)
+			for i = m - 1; i >= l; i-- {
+				f := s * sub[i]
+				b := c * sub[i]
+				r = math.Hypot(f, g)
+				sub[i+1] = r
+				if r == 0 {
+					d[i+1] -= p
+					sub[m] = 0
+					break
+				}
+				s, c = f/r, g/r
+				g = d[i+1] - p
+				r = (d[i]-g)*s + 2*c*b
+				p = s * r
+				d[i+1] = g + p
+				g = c*r - b
+				z[i], z[i+1] = c*z[i]-s*z[i+1], s*z[i]+c*z[i+1]
+			}
+			if r == 0 && i >= l {
+				continue
+			}
+			d[l] -= p
+			sub[l] = g
+			sub[m] = 0
+		}
+	}
+	return z, true
+}
+
+// Computes a Gaussian rule by the Golub-Welsch algorithm. The nodes are the
+// eigenvalues of the Jacobi matrix built from the recurrence coefficients
+// of the monic orthogonal polynomials, p_(k+1) = (x - a_k) p_k - b_k p_(k-1).
+// The weights are mu0 times the squared first eigenvector components, where
+// mu0 is the integral of the weight function. The nodes are sorted in
+// ascending order. A value of nil means the eigenvalue iteration failed.
+func golubWelsch(a []float64, b []float64, mu0 float64) (rule [][2]float64) {
+	n := len(a)
+	d := make([]float64, n)
+	copy(d, a)
+	e := make([]float64, n)
+	for k := 1; k < n; k++ {
+		e[k-1] = math.Sqrt(b[k])
+	}
+	z, ok := tridiagonalEigen(d, e)
+	if !ok {
+		return nil
+	}
+	rule = make([][2]float64, n)
+	for i := 0; i < n; i++ {
+		rule[i] = [2]float64{d[i], mu0 * z[i] * z[i]}
+	}
+	// Insertion sort, the QL method leaves the nodes nearly ordered
+	for i := 1; i < n; i++ {
+		for j := i; j > 0 && rule[j][0] < rule[j-1][0]; j-- {
+			rule[j], rule[j-1] = rule[j-1], rule[j]
+		}
+	}
+	return
+}
+
+// Computes the n-point Gauss-Laguerre rule for the weight e^-x on [0, Inf)
+func generateGaussLaguerre(n int) [][2]float64 {
+	a, b := make([]float64, n), make([]float64, n)
+	for k := 0; k < n; k++ {
+		a[k] = 2*float64(k) + 1
+		b[k] = float64(k) * float64(k)
+	}
+	return golubWelsch(a, b, 1)
+}
+
+// Computes the n-point Gauss-Hermite rule for the weight e^-x^2 on
+// (-Inf, Inf)
+func generateGaussHermite(n int) [][2]float64 {
+	a, b := make([]float64, n), make([]float64, n)
+	for k := 0; k < n; k++ {
+		b[k] = float64(k) / 2
+	}
+	return golubWelsch(a, b, math.Sqrt(math.Pi))
+}
+
+// Computes the n-point Gauss-Jacobi rule for the weight
+// (1-x)^alpha (1+x)^beta on [-1, 1]
+func generateGaussJacobi(n int, alpha float64, beta float64) [][2]float64 {
+	a, b := make([]float64, n), make([]float64, n)
+	ab := alpha + beta
+	// The general formulas are 0/0 for k = 0 and, if ab = -1, for k = 1
+	a[0] = (beta - alpha) / (ab + 2)
+	for k := 1; k < n; k++ {
+		kk := float64(k)
+		s := 2*kk + ab
+		a[k] = (beta*beta - alpha*alpha) / (s * (s + 2))
+		if k == 1 {
+			b[k] = 4 * (1 + alpha) * (1 + beta) / ((2 + ab) * (2 + ab) *
+				(3 + ab))
+		} else {
+			b[k] = 4 * kk * (kk + alpha) * (kk + beta) * (kk + ab) /
+				(s * s * (s + 1) * (s - 1))
+		}
+	}
+	lgA, _ := math.Lgamma(alpha + 1)
+	lgB, _ := math.Lgamma(beta + 1)
+	lgAB, _ := math.Lgamma(ab + 2)
+	mu0 := math.Exp((ab+1)*math.Ln2 + lgA + lgB - lgAB)
+	return golubWelsch(a, b, mu0)
+}
+
+// Returns a copy of a cached rule, or nil if there is none
+func copyGaussRule(cached [][2]float64) (rule [][2]float64) {
+	if cached == nil {
+		return nil
+	}
+	rule = make([][2]float64, len(cached))
+	copy(rule, cached)
+	return
+}
+
+// Applies a rule with a built-in weight function to f, without any change
+// of variable. A `result` value of NaN means the rule is unavailable.
+func applyWeightedGaussRule(f SingleVarFunction,
+	rule [][2]float64) (result float64) {
+	if rule == nil {
+		return math.NaN()
+	}
+	for _, node := range rule {
+		result += node[1] * f(node[0])
+	}
+	return
+}
+
+// GaussLaguerreNodes returns the nodes and weights of the n-point
+// Gauss-Laguerre rule, which approximates the integral of e^-x f(x) over
+// [0, Inf) by the sum of weight * f(node). Rules are computed once per n by
+// the Golub-Welsch algorithm and then cached. Note that for large n the
+// outer weights underflow to zero.
+// A value of nil is returned for n < 1.
+func GaussLaguerreNodes(n int) (rule [][2]float64) {
+	if n < 1 {
+		return nil
+	}
+	return copyGaussRule(cachedGaussRule(gaussRuleKey{family: gaussLaguerre,
+		n: n}, func() [][2]float64 {
+		return generateGaussLaguerre(n)
+	}))
+}
+
+// GaussHermiteNodes returns the nodes and weights of the n-point
+// Gauss-Hermite rule, which approximates the integral of e^-x^2 f(x) over
+// (-Inf, Inf) by the sum of weight * f(node). Rules are computed once per n
+// by the Golub-Welsch algorithm and then cached.
+// A value of nil is returned for n < 1.
+func GaussHermiteNodes(n int) (rule [][2]float64) {
+	if n < 1 {
+		return nil
+	}
+	return copyGaussRule(cachedGaussRule(gaussRuleKey{family: gaussHermite,
+		n: n}, func() [][2]float64 {
+		return generateGaussHermite(n)
+	}))
+}
+
+// GaussJacobiNodes returns the nodes and weights of the n-point Gauss-Jacobi
+// rule, which approximates the integral of (1-x)^alpha (1+x)^beta f(x) over
+// [-1, 1] by the sum of weight * f(node). Both alpha and beta must be
+// greater than -1. Rules are computed once per (n, alpha, beta) by the
+// Golub-Welsch algorithm and then cached.
+// A value of nil is returned for invalid arguments.
+func GaussJacobiNodes(n int, alpha float64, beta float64) (rule [][2]float64) {
+	if n < 1 || !(alpha > -1) || !(beta > -1) {
+		return nil
+	}
+	return copyGaussRule(cachedGaussRule(gaussRuleKey{gaussJacobi, n, alpha,
+		beta}, func() [][2]float64 {
+		return generateGaussJacobi(n, alpha, beta)
+	}))
+}
+
+// NIntegrateGaussLaguerre finds the numeric value of the integral of
+// e^-x * f(x) in the interval [0, Inf) using the n-point Gauss-Laguerre
+// rule. Only f is evaluated, the weight e^-x is built into the rule, so f
+// should be smooth and grow no faster than a polynomial.
+// As with NIntegrateGaussLegendre, no error estimate is available.
+// A `result` value of NaN means n < 1.
+func NIntegrateGaussLaguerre(f SingleVarFunction, n int) (result float64) {
+	if n < 1 {
+		return math.NaN()
+	}
+	return applyWeightedGaussRule(f, cachedGaussRule(
+		gaussRuleKey{family: gaussLaguerre, n: n}, func() [][2]float64 {
+			return generateGaussLaguerre(n)
+		}))
+}
+
+// NIntegrateGaussHermite finds the numeric value of the integral of
+// e^-x^2 * f(x) in the interval (-Inf, Inf) using the n-point Gauss-Hermite
+// rule. Only f is evaluated, the weight e^-x^2 is built into the rule.
+// Expectations under a normal distribution with mean mu and deviation
+// sigma follow from x -> mu + sqrt(2)*sigma*x and a factor of 1/sqrt(pi).
+// A `result` value of NaN means n < 1.
+func NIntegrateGaussHermite(f SingleVarFunction, n int) (result float64) {
+	if n < 1 {
+		return math.NaN()
+	}
+	return applyWeightedGaussRule(f, cachedGaussRule(
+		gaussRuleKey{family: gaussHermite, n: n}, func() [][2]float64 {
+			return generateGaussHermite(n)
+		}))
+}
+
+// NIntegrateGaussJacobi finds the numeric value of the integral of
+// (1-x)^alpha * (1+x)^beta * f(x) in the interval [-1, 1] using the n-point
+// Gauss-Jacobi rule. Only f is evaluated, so algebraic endpoint
+// singularities of the weight cost no accuracy.
+// A `result` value of NaN means n < 1 or alpha, beta <= -1.
+func NIntegrateGaussJacobi(f SingleVarFunction, alpha float64, beta float64,
+	n int) (result float64) {
+	if n < 1 || !(alpha > -1) || !(beta > -1) {
+		return math.NaN()
+	}
+	return applyWeightedGaussRule(f, cachedGaussRule(
+		gaussRuleKey{gaussJacobi, n, alpha, beta}, func() [][2]float64 {
+			return generateGaussJacobi(n, alpha, beta)
+		}))
+}
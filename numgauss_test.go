@@ -0,0 +1,106 @@
+// numgauss_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests the generated 7-point rule against the hard-coded Gauss table
+func TestGaussLegendreMatchesTable(t *testing.T) {
+	rule := GaussLegendreNodes(gaussNodeCount)
+	for _, expected := range gaussNodes {
+		found := false
+		for _, node := range rule {
+			if math.Abs(node[0]-expected[0]) < 1e-15 &&
+				math.Abs(node[1]-expected[1]) < 1e-15 {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("Node", expected, "is missing from", rule)
+		}
+	}
+}
+
+// Tests that the n-point rule integrates x^(2n-1) and x^(2n-2) exactly
+func TestGaussLegendreExactness(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 10, 25} {
+		odd := func(x float64) float64 { return math.Pow(x, float64(2*n-1)) }
+		even := func(x float64) float64 { return math.Pow(x, float64(2*n-2)) }
+		if result := NIntegrateGaussLegendre(odd, 0, 1, n); math.Abs(
+			result-1/float64(2*n)) > 1e-13 {
+			t.Error("Rule", n, "integrated x^", 2*n-1, "as", result)
+		}
+		if result := NIntegrateGaussLegendre(even, -1, 1, n); math.Abs(
+			result-2/float64(2*n-1)) > 1e-13 {
+			t.Error("Rule", n, "integrated x^", 2*n-2, "as", result)
+		}
+	}
+}
+
+// Tests rules with hundreds of nodes: the nodes must be sorted within
+// (-1, 1), the weights must sum to 2, and smooth integrals, including a
+// rapidly oscillating one, must be exact to rounding
+func TestGaussLegendreHighOrder(t *testing.T) {
+	for _, n := range []int{100, 500, 1000} {
+		rule := GaussLegendreNodes(n)
+		sum := 0.0
+		for i, node := range rule {
+			if node[0] <= -1 || node[0] >= 1 ||
+				(i > 0 && node[0] <= rule[i-1][0]) {
+				t.Error("Rule", n, "has node", i, "at", node[0])
+			}
+			sum += node[1]
+		}
+		if math.Abs(sum-2) > 1e-13 {
+			t.Error("Rule", n, "has weights summing to", sum)
+		}
+		if result := NIntegrateGaussLegendre(math.Exp, -1, 1, n); math.Abs(
+			result-(math.E-1/math.E)) > 1e-13 {
+			t.Error("Rule", n, "integrated e^x as", result)
+		}
+		wave := func(x float64) float64 { return math.Cos(50 * x) }
+		if result := NIntegrateGaussLegendre(wave, -1, 1, n); math.Abs(
+			result-math.Sin(50)/25) > 1e-13 {
+			t.Error("Rule", n, "integrated cos(50x) as", result)
+		}
+	}
+}
+
+// Weighted integrals with known values, each for a few rule sizes
+var testWeightedIntegrals = []struct {
+	integrate func(f SingleVarFunction, n int) float64
+	f         SingleVarFunction
+	exact     float64
+}{
+	{NIntegrateGaussLaguerre, func(x float64) float64 { return x * x * x }, 6},
+	{NIntegrateGaussLaguerre, math.Sin, 0.5},
+	{NIntegrateGaussHermite, sqr, math.Sqrt(math.Pi) / 2},
+	{NIntegrateGaussHermite, math.Cos, math.Sqrt(math.Pi) * math.Exp(-0.25)},
+	{func(f SingleVarFunction, n int) float64 {
+		return NIntegrateGaussJacobi(f, 0.5, 0.5, n)
+	}, func(float64) float64 { return 1 }, math.Pi / 2},
+	{func(f SingleVarFunction, n int) float64 {
+		return NIntegrateGaussJacobi(f, -0.5, -0.5, n)
+	}, func(x float64) float64 { return sqr(sqr(x)) }, 3 * math.Pi / 8},
+	{func(f SingleVarFunction, n int) float64 {
+		return NIntegrateGaussJacobi(f, 0, 0, n)
+	}, math.Exp, math.E - 1/math.E},
+}
+
+// Tests the Golub-Welsch based rules
+func TestWeightedGaussTable(t *testing.T) {
+	for i, tt := range testWeightedIntegrals {
+		for _, n := range []int{30, 100} {
+			if result := tt.integrate(tt.f, n); math.Abs(
+				result-tt.exact) > 1e-12 {
+				t.Error("Integral", i, "with", n, "nodes produced", result,
+					"instead of", tt.exact)
+			}
+		}
+	}
+	if GaussJacobiNodes(5, -1, 0) != nil {
+		t.Error("Accepted alpha = -1")
+	}
+}
@@ -1,6 +1,9 @@
 package gonumeth
 
-import "math"
+import (
+	"container/heap"
+	"math"
+)
 
 // Nodes for the non-adaptive Gauss-Kronrod method
 const (
@@ -34,86 +37,1324 @@ var kronrodNodes = [kronrodNodeCount][2]float64{
 	{0.9914553711208126392068547, 0.0229353220105292249637320},
 	{-0.9914553711208126392068547, 0.0229353220105292249637320}}
 
+// Positions of the Gauss nodes within the Kronrod table. The 15-point Kronrod
+// rule extends the 7-point Gauss rule, so its nodes are a superset.
+var kronrodGaussIndex = [gaussNodeCount]int{0, 3, 4, 7, 8, 11, 12}
+
 // NIntegrateGaussKronrodNonAdaptive attempts to find the numeric value of the
 // integral of f in the interval [a, b] using the Gauss-Kronrod rules.
 // This method does not adapt to the "stiffness" of the function.
 // If the error is greater than any of the targets (goalErrorAbs or
-// goalErrorRel * result), the function has failed.
+// goalErrorRel * |result|), the function has failed and result is NaN.
+// NIntegrateGaussKronrodNonAdaptiveDetailed keeps the estimate instead.
 // The method will reuse all values of the function calculated.
 // The function returns the result and error estimation as result and
 // errorEstimate respectively.
 func NIntegrateGaussKronrodNonAdaptive(f SingleVarFunction, a float64,
 	b float64, goalErrorAbs float64, goalErrorRel float64) (result float64,
 	errorEstimate float64) {
+	detailed := NIntegrateGaussKronrodNonAdaptiveDetailed(f, a, b,
+		goalErrorAbs, goalErrorRel)
+	result, errorEstimate = detailed.Value, detailed.ErrorEstimate
+	if detailed.Status != IntegrationConverged {
+		result = math.NaN()
+	}
+	return
+}
+
+// NIntegrateGaussKronrodNonAdaptiveDetailed works the same way as
+// NIntegrateGaussKronrodNonAdaptive, but returns an IntegrationResult. When
+// the targets are missed the estimate is kept and the status tells why.
+// The 7 Gauss nodes are among the 15 Kronrod nodes, so f is evaluated 15
+// times.
+func NIntegrateGaussKronrodNonAdaptiveDetailed(f SingleVarFunction,
+	a float64, b float64, goalErrorAbs float64,
+	goalErrorRel float64) (detailed IntegrationResult) {
 	var (
 		gaussApprox   float64 = 0
 		kronrodApprox float64 = 0
 		xpoint        float64
-		fvalue        float64
+		fvalues       [kronrodNodeCount]float64
 	)
-	for i := 0; i < gaussNodeCount; i++ {
-		xpoint = gaussNodes[i][0]*(b-a)/2 + (a+b)/2
-		fvalue = f(xpoint)
-		gaussApprox += fvalue * gaussNodes[i][1]
-	}
 	for i := 0; i < kronrodNodeCount; i++ {
 		xpoint = kronrodNodes[i][0]*(b-a)/2 + (a+b)/2
-		fvalue = f(xpoint)
-		kronrodApprox += fvalue * kronrodNodes[i][1]
+		fvalues[i] = f(xpoint)
+		kronrodApprox += fvalues[i] * kronrodNodes[i][1]
 	}
-	errorEstimate = (b - a) * 100 * math.Abs(gaussApprox-kronrodApprox)
-	result = (b - a) * 0.25 * (kronrodApprox + gaussApprox)
-	if errorEstimate > goalErrorAbs || errorEstimate > result*goalErrorRel {
-		result = math.NaN()
+	for i := 0; i < gaussNodeCount; i++ {
+		gaussApprox += fvalues[kronrodGaussIndex[i]] * gaussNodes[i][1]
+	}
+	detailed.ErrorEstimate = math.Abs((b - a) * 100 *
+		(gaussApprox - kronrodApprox))
+	detailed.Value = (b - a) * 0.25 * (kronrodApprox + gaussApprox)
+	detailed.Evaluations = kronrodNodeCount
+	detailed.Subintervals = 1
+	if !integrationGoalsMet(detailed.Value, detailed.ErrorEstimate,
+		goalErrorAbs, goalErrorRel) {
+		detailed.Status = unconvergedStatus(detailed.Value,
+			detailed.ErrorEstimate)
 	}
 	return
 }
 
-// Recursive function for calculating the integral by adaptive Simspon's method
+// Defaults of the adaptive Simpson's method, which bound its recursion
+const (
+	simpsonDefaultMaxDepth       int = 50
+	simpsonDefaultMaxEvaluations int = 1 << 20
+)
+
+// Checks whether [a, b] can be bisected twice, which the recursion of the
+// adaptive Simpson's method needs to place its next nodes.
+func canBisectTwice(a float64, b float64) bool {
+	c := (a + b) / 2
+	d := (a + c) / 2
+	e := (c + b) / 2
+	return a < d && d < c && c < e && e < b
+}
+
+// Recursive function for calculating the integral by adaptive Simspon's method.
+// The values of f at the ends, the midpoint and the two quarter points of
+// [a, b] are known; the interval lies depth bisections below the original
+// one. The evaluations, accepted subintervals and the worst status met are
+// accumulated in detailed.
 func simpsonAdaptiveRec(f SingleVarFunction, a float64, b float64,
 	goalErrorAbs float64, goalErrorRel float64, S float64, fa float64,
-	fb float64, fc float64) (result float64, errorEstimate float64) {
+	fb float64, fc float64, fd float64, fe float64, depth int,
+	limits IntegrationOptions, detailed *IntegrationResult) (result float64,
+	errorEstimate float64) {
 	c := (a + b) / 2
 	h := b - a
 	d := (a + c) / 2
 	e := (c + b) / 2
-	fd := f(d)
-	fe := f(e)
 	S_left := (h / 12) * (fa + 4*fd + fc)
 	S_right := (h / 12) * (fc + 4*fe + fb)
 	S2 := S_left + S_right
 	errorEstimate = (S2 - S) / 15
 	err := math.Abs(errorEstimate)
-	if err <= goalErrorAbs && err <= S*goalErrorRel && err <= S2*goalErrorRel {
-		result = S2 + errorEstimate
-		errorEstimate = err
+	var status IntegrationStatus = IntegrationConverged
+	switch {
+	case err <= goalErrorAbs && err <= math.Abs(S)*goalErrorRel &&
+		err <= math.Abs(S2)*goalErrorRel:
+	case !isFinite(S2):
+		status = IntegrationDivergent
+	case !canBisectTwice(a, c) || !canBisectTwice(c, b):
+		status = IntegrationRoundoff
+	default:
+		var ok bool
+		// Each half needs its two quarter points
+		ok, status = limits.allow(detailed.Evaluations, 4, depth+1, h/2)
+		if !ok {
+			break
+		}
+		fdl := f((a + d) / 2)
+		fdr := f((d + c) / 2)
+		fel := f((c + e) / 2)
+		fer := f((e + b) / 2)
+		detailed.Evaluations += 4
+		res1, err1 := simpsonAdaptiveRec(f, a, c, goalErrorAbs/2,
+			goalErrorRel, S_left, fa, fc, fd, fdl, fdr, depth+1, limits,
+			detailed)
+		res2, err2 := simpsonAdaptiveRec(f, c, b, goalErrorAbs/2,
+			goalErrorRel, S_right, fc, fb, fe, fel, fer, depth+1, limits,
+			detailed)
+		result = res1 + res2
+		errorEstimate = err1 + err2
 		return
 	}
-	res1, err1 := simpsonAdaptiveRec(f, a, c, goalErrorAbs/2, goalErrorRel,
-		S_left, fa, fc, fd)
-	res2, err2 := simpsonAdaptiveRec(f, c, b, goalErrorAbs/2, goalErrorRel,
-		S_right, fc, fb, fe)
-	result = res1 + res2
-	errorEstimate = err1 + err2
+	detailed.Status = worseStatus(detailed.Status, status)
+	detailed.Subintervals++
+	result = S2 + errorEstimate
+	errorEstimate = err
 	return
 }
 
 // NIntegrateSimpsonAdaptive attempts to find the numeric value of the
 // integral of f in the interval [a, b] using the Simspon rule by
 // further "sectioning" subintervals until desired error goals are achieved.
+// The recursion is bounded by the default limits of
+// NIntegrateSimpsonAdaptiveDetailed.
 // The function returns the result and error estimation as result and
 // errorEstimate respectively.
 func NIntegrateSimpsonAdaptive(f SingleVarFunction, a float64, b float64,
 	goalErrorAbs float64, goalErrorRel float64) (result float64,
 	errorEstimate float64) {
+	detailed := NIntegrateSimpsonAdaptiveDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, nil)
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateSimpsonAdaptiveDetailed works the same way as
+// NIntegrateSimpsonAdaptive, but returns an IntegrationResult and accepts
+// options. Subintervals that would break a limit are accepted as they are,
+// so the result always covers [a, b], and the status reports the limit.
+// Subintervals too narrow to be split are reported with IntegrationRoundoff
+// and non-finite values of f with IntegrationDivergent. By default at most
+// 2^20 evaluations and 50 bisections are used, and there is no minimum
+// width. The first estimate costs 5 evaluations, so a smaller
+// MaxEvaluations evaluates nothing and returns a NaN value with an infinite
+// error estimate and IntegrationMaxEvaluations.
+func NIntegrateSimpsonAdaptiveDetailed(f SingleVarFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	limits := options.withDefaults(simpsonDefaultMaxEvaluations,
+		simpsonDefaultMaxDepth)
+	if limits.MaxEvaluations < 5 {
+		// Not even the first estimate fits into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = IntegrationMaxEvaluations
+		return
+	}
 	c := (a + b) / 2
 	h := b - a
 	var (
 		fa     float64 = f(a)
 		fb     float64 = f(b)
 		fc     float64 = f(c)
+		fd     float64 = f((a + c) / 2)
+		fe     float64 = f((c + b) / 2)
 		S_init float64 = h / 6 * (fa + 4*fc + fb)
 	)
-	return simpsonAdaptiveRec(f, a, b, goalErrorAbs, goalErrorRel,
-		S_init, fa, fb, fc)
+	detailed.Evaluations = 5
+	detailed.Value, detailed.ErrorEstimate = simpsonAdaptiveRec(f, a, b,
+		goalErrorAbs, goalErrorRel, S_init, fa, fb, fc, fd, fe, 0, limits,
+		&detailed)
+	return
+}
+
+// Applies the 7-point Gauss and the 15-point Kronrod rules to f in [a, b].
+// Only the 15 Kronrod nodes are evaluated, the Gauss sum reuses them.
+// The Kronrod value is returned as result, the difference between the
+// two rules as errorEstimate, and the Kronrod rule applied to |f| as
+// absResult, which measures the rounding in result.
+func gaussKronrodRule(f SingleVarFunction, a float64,
+	b float64) (result float64, errorEstimate float64, absResult float64) {
+	var (
+		center     float64 = (a + b) / 2
+		halfLength float64 = (b - a) / 2
+		fvalues    [kronrodNodeCount]float64
+	)
+	for i := 0; i < kronrodNodeCount; i++ {
+		fvalues[i] = f(center + kronrodNodes[i][0]*halfLength)
+	}
+	return gaussKronrodSums(fvalues[:], halfLength)
+}
+
+// Combines the values of f at the Kronrod nodes of an interval with the
+// given half length into the results of gaussKronrodRule.
+func gaussKronrodSums(fvalues []float64, halfLength float64) (result float64,
+	errorEstimate float64, absResult float64) {
+	var (
+		gaussApprox   float64 = 0
+		kronrodApprox float64 = 0
+	)
+	for i := 0; i < kronrodNodeCount; i++ {
+		kronrodApprox += fvalues[i] * kronrodNodes[i][1]
+		absResult += math.Abs(fvalues[i]) * kronrodNodes[i][1]
+	}
+	for i := 0; i < gaussNodeCount; i++ {
+		gaussApprox += fvalues[kronrodGaussIndex[i]] * gaussNodes[i][1]
+	}
+	result = kronrodApprox * halfLength
+	errorEstimate = math.Abs((kronrodApprox - gaussApprox) * halfLength)
+	absResult *= math.Abs(halfLength)
+	return
+}
+
+// A subinterval of an adaptive integration together with its partial
+// result, error estimate, integral of |f| and the number of bisections
+// that produced it. For a complex integrand imagResult holds the imaginary
+// part of the result, and the error estimate bounds the modulus of the
+// error; it is 0 otherwise.
+type gkSubinterval struct {
+	a             float64
+	b             float64
+	result        float64
+	imagResult    float64
+	errorEstimate float64
+	absResult     float64
+	depth         int
+}
+
+// A max-heap of subintervals ordered by their error estimates.
+// It implements heap.Interface.
+type gkSubintervalHeap []gkSubinterval
+
+func (h gkSubintervalHeap) Len() int { return len(h) }
+
+func (h gkSubintervalHeap) Less(i, j int) bool {
+	return h[i].errorEstimate > h[j].errorEstimate
+}
+
+func (h gkSubintervalHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *gkSubintervalHeap) Push(x interface{}) {
+	*h = append(*h, x.(gkSubinterval))
+}
+
+func (h *gkSubintervalHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Sums the results and error estimates of all subintervals.
+func (h gkSubintervalHeap) totals() (result float64, imagResult float64,
+	errorEstimate float64) {
+	for _, s := range h {
+		result += s.result
+		imagResult += s.imagResult
+		errorEstimate += s.errorEstimate
+	}
+	return
+}
+
+// Checks whether an adaptive Gauss-Kronrod integration should stop before
+// its next bisection, because the targets are met or because the estimates
+// are not finite or are at the rounding level of the integral of |f|.
+func gaussKronrodDone(result float64, errorEstimate float64,
+	absResult float64, goalErrorAbs float64,
+	goalErrorRel float64) (done bool, status IntegrationStatus) {
+	switch {
+	case integrationGoalsMet(result, errorEstimate, goalErrorAbs,
+		goalErrorRel):
+		return true, IntegrationConverged
+	case !isFinite(result) || !isFinite(errorEstimate):
+		return true, IntegrationDivergent
+	case errorEstimate <= integrationRoundoffLevel*absResult:
+		return true, IntegrationRoundoff
+	}
+	return false, IntegrationConverged
+}
+
+// Pops the subinterval with the largest error estimate if it can be
+// bisected within the limits, given the evaluations spent so far. If not,
+// it stays in the heap and the status tells why.
+func (h *gkSubintervalHeap) popSplittable(limits IntegrationOptions,
+	evaluations int) (worst gkSubinterval, ok bool,
+	status IntegrationStatus) {
+	worst = heap.Pop(h).(gkSubinterval)
+	mid := (worst.a + worst.b) / 2
+	if mid <= worst.a || mid >= worst.b {
+		// The interval can not be split any further
+		heap.Push(h, worst)
+		return worst, false, IntegrationRoundoff
+	}
+	ok, status = limits.allow(evaluations, 2*kronrodNodeCount,
+		worst.depth+1, mid-worst.a)
+	if !ok {
+		heap.Push(h, worst)
+	}
+	return
+}
+
+// NIntegrateGaussKronrod attempts to find the numeric value of the integral
+// of f in the interval [a, b] using the Gauss-Kronrod rules adaptively.
+// The subinterval with the largest error estimate is repeatedly bisected
+// until the error is within both targets (goalErrorAbs and
+// goalErrorRel * |result|) or until maxEvaluations function evaluations have
+// been spent. A value of 0 for maxEvaluations means there is no limit.
+// When the budget runs out the best estimate so far is returned, so the
+// caller should compare errorEstimate against the targets, or use
+// NIntegrateGaussKronrodDetailed which reports why it stopped.
+// The first estimate costs 15 evaluations, so for a maxEvaluations below
+// that f is not evaluated, result is NaN and errorEstimate is infinite.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateGaussKronrod(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result float64, errorEstimate float64) {
+	detailed := NIntegrateGaussKronrodDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateGaussKronrodDetailed works the same way as
+// NIntegrateGaussKronrod, but returns an IntegrationResult and accepts
+// options. The bisection stops as soon as splitting the worst subinterval
+// would break a limit, with the status of that limit; by default there are
+// no limits. It also stops when the worst subinterval can not be split, or
+// when the error estimate is at the rounding level of the integral of |f|;
+// both are reported as IntegrationRoundoff. The latter lets integrals whose
+// value is zero terminate without a budget. The first rule costs 15
+// evaluations, so a smaller MaxEvaluations evaluates nothing and returns a
+// NaN value with an infinite error estimate and IntegrationMaxEvaluations.
+func NIntegrateGaussKronrodDetailed(f SingleVarFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	evaluate := func(intervals []gkSubinterval) {
+		for i := range intervals {
+			s := &intervals[i]
+			s.result, s.errorEstimate, s.absResult = gaussKronrodRule(f, s.a,
+				s.b)
+		}
+	}
+	detailed, _ = gaussKronrodAdaptive(evaluate, a, b, goalErrorAbs,
+		goalErrorRel, options.withDefaults(0, 0), 1)
+	return
+}
+
+// Runs the adaptive Gauss-Kronrod bisection of [a, b]. Each round bisects
+// the subintervals with the largest error estimates, up to maxBatch of
+// them and only as many as needed for the rest of the error to be within
+// the targets, and passes all their halves to evaluate at once, which must
+// fill in the rule results. The halves are merged in order, so the result
+// only depends on maxBatch. The targets apply to the modulus of the result,
+// whose imaginary part is returned as imagValue for complex integrands.
+func gaussKronrodAdaptive(evaluate func(intervals []gkSubinterval),
+	a float64, b float64, goalErrorAbs float64, goalErrorRel float64,
+	limits IntegrationOptions, maxBatch int) (detailed IntegrationResult,
+	imagValue float64) {
+	if limits.MaxEvaluations != 0 && limits.MaxEvaluations < kronrodNodeCount {
+		// Not even the first rule fits into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = IntegrationMaxEvaluations
+		return detailed, math.NaN()
+	}
+	first := []gkSubinterval{{a: a, b: b}}
+	evaluate(first)
+	var (
+		result        float64            = first[0].result
+		imagResult    float64            = first[0].imagResult
+		errorEstimate float64            = first[0].errorEstimate
+		absResult     float64            = first[0].absResult
+		evaluations   int                = kronrodNodeCount
+		intervals     *gkSubintervalHeap = &gkSubintervalHeap{first[0]}
+		status        IntegrationStatus
+	)
+	for {
+		var done bool
+		modulus := math.Hypot(result, imagResult)
+		if done, status = gaussKronrodDone(modulus, errorEstimate, absResult,
+			goalErrorAbs, goalErrorRel); done {
+			break
+		}
+		var (
+			target    float64 = math.Min(goalErrorAbs, modulus*goalErrorRel)
+			remaining float64 = errorEstimate
+			batch     []gkSubinterval
+		)
+		for len(batch) < maxBatch && (len(batch) == 0 || remaining > target) {
+			worst, ok, limitStatus := intervals.popSplittable(limits,
+				evaluations+2*kronrodNodeCount*len(batch))
+			if !ok {
+				status = limitStatus
+				break
+			}
+			batch = append(batch, worst)
+			remaining -= worst.errorEstimate
+		}
+		if len(batch) == 0 {
+			break
+		}
+		halves := make([]gkSubinterval, 2*len(batch))
+		for i, worst := range batch {
+			mid := (worst.a + worst.b) / 2
+			halves[2*i] = gkSubinterval{a: worst.a, b: mid,
+				depth: worst.depth + 1}
+			halves[2*i+1] = gkSubinterval{a: mid, b: worst.b,
+				depth: worst.depth + 1}
+		}
+		evaluate(halves)
+		evaluations += len(halves) * kronrodNodeCount
+		for i, worst := range batch {
+			left, right := halves[2*i], halves[2*i+1]
+			heap.Push(intervals, left)
+			heap.Push(intervals, right)
+			result += left.result + right.result - worst.result
+			imagResult += left.imagResult + right.imagResult -
+				worst.imagResult
+			errorEstimate += left.errorEstimate + right.errorEstimate -
+				worst.errorEstimate
+			absResult += left.absResult + right.absResult - worst.absResult
+		}
+	}
+	// Summing afresh avoids the rounding accumulated by the updates above
+	detailed.Value, imagValue, detailed.ErrorEstimate = intervals.totals()
+	detailed.Evaluations = evaluations
+	detailed.Subintervals = intervals.Len()
+	detailed.Status = status
+	return
+}
+
+// Maps an integral of f over an interval with infinite bounds onto a finite
+// interval of t by a change of variable. The returned function g already
+// includes the Jacobian, so the integral of g over [ta, tb] equals the
+// integral of f over [a, b]. The bounds must satisfy a < b.
+//
+//	[a, +Inf)    x = a + (1-t)/t,  t in (0, 1]
+//	(-Inf, b]    x = b - (1-t)/t,  t in (0, 1]
+//	(-Inf, +Inf) x = t/(1-t^2),    t in (-1, 1)
+//
+// The finite endpoints t = 0 and t = -1, 1 are never evaluated by the
+// Gauss-Kronrod rules, which keeps g well defined.
+func infiniteIntervalTransform(f SingleVarFunction, a float64,
+	b float64) (g SingleVarFunction, ta float64, tb float64) {
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		g = func(t float64) float64 {
+			d := 1 - t*t
+			x := t / d
+			if math.IsInf(x, 0) {
+				return 0
+			}
+			return f(x) * (1 + t*t) / (d * d)
+		}
+		return g, -1, 1
+	case math.IsInf(b, 1):
+		g = func(t float64) float64 {
+			x := a + (1-t)/t
+			if math.IsInf(x, 0) {
+				return 0
+			}
+			return f(x) / (t * t)
+		}
+		return g, 0, 1
+	case math.IsInf(a, -1):
+		g = func(t float64) float64 {
+			x := b - (1-t)/t
+			if math.IsInf(x, 0) {
+				return 0
+			}
+			return f(x) / (t * t)
+		}
+		return g, 0, 1
+	default:
+		return f, a, b
+	}
+}
+
+// NIntegrateInfinite attempts to find the numeric value of the integral of f
+// in the interval [a, b], where any of a and b may be infinite. Intervals
+// of the form [a, +Inf), (-Inf, b] and (-Inf, +Inf) are mapped onto finite
+// ones by a change of variable and integrated by NIntegrateGaussKronrod,
+// which receives goalErrorAbs, goalErrorRel and maxEvaluations unchanged.
+// Finite intervals are passed on directly.
+// Since the change of variable preserves the value of the integral, the
+// error estimate of the transformed integral is returned as is. It is
+// reliable only if f decays fast enough for the integral to exist.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateInfinite(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result float64, errorEstimate float64) {
+	detailed := NIntegrateInfiniteDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateInfiniteDetailed works the same way as NIntegrateInfinite, but
+// passes the options to NIntegrateGaussKronrodDetailed and returns its
+// IntegrationResult. The subintervals, and so MinWidth, are measured in the
+// transformed variable.
+func NIntegrateInfiniteDetailed(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	if a == b {
+		return
+	}
+	if a > b {
+		detailed = NIntegrateInfiniteDetailed(f, b, a, goalErrorAbs,
+			goalErrorRel, options)
+		detailed.Value = -detailed.Value
+		return
+	}
+	g, ta, tb := infiniteIntervalTransform(f, a, b)
+	return NIntegrateGaussKronrodDetailed(g, ta, tb, goalErrorAbs,
+		goalErrorRel, options)
+}
+
+// Parameters of the tanh-sinh quadrature. Past tanhSinhMaxT the nodes are
+// closer to the endpoints than the float64 resolution allows. The first
+// level, with a step of 1, has at most tanhSinhFirstNodes nodes.
+const (
+	tanhSinhMaxT       float64 = 6.5
+	tanhSinhMaxLevels  int     = 12
+	tanhSinhMinLevels  int     = 3
+	tanhSinhFirstNodes int     = 13
+)
+
+// Sums the tanh-sinh terms for the nodes t = k*h, where k runs over
+// first, first+step, ... while t <= tanhSinhMaxT. The abscissae are
+// computed from their distance to the closer endpoint, so no node is ever
+// placed exactly at a or b.
+func tanhSinhSum(f SingleVarFunction, a float64, b float64, h float64,
+	first int, step int) (sum float64) {
+	halfLength := (b - a) / 2
+	for k := first; float64(k)*h <= tanhSinhMaxT; k += step {
+		t := float64(k) * h
+		u := math.Pi / 2 * math.Sinh(t)
+		coshU := math.Cosh(u)
+		weight := halfLength * math.Pi / 2 * math.Cosh(t) / (coshU * coshU)
+		if weight == 0 {
+			// The weights only decrease from here on
+			break
+		}
+		if k == 0 {
+			sum += weight * f((a+b)/2)
+			continue
+		}
+		// Distance of both nodes from their endpoints: 1 - tanh(u)
+		distance := halfLength * 2 / (math.Exp(2*u) + 1)
+		xleft, xright := a+distance, b-distance
+		if xleft > a && xleft < b {
+			sum += weight * f(xleft)
+		}
+		if xright < b && xright > a {
+			sum += weight * f(xright)
+		}
+	}
+	return
+}
+
+// NIntegrateTanhSinh attempts to find the numeric value of the integral of f
+// in the interval [a, b] using the tanh-sinh (double exponential) rule.
+// The nodes cluster doubly exponentially towards the endpoints, which are
+// never evaluated, so integrable singularities at a or b (such as
+// 1/sqrt(x) or log(x) at 0) are handled well.
+// The step is halved on each level, reusing all previous function values,
+// until the change between levels is within both targets (goalErrorAbs and
+// goalErrorRel * |result|). If this does not happen within a fixed number of
+// levels, the last estimate is returned along with its error estimate.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateTanhSinh(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64) (result float64,
+	errorEstimate float64) {
+	detailed := NIntegrateTanhSinhDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, nil)
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateTanhSinhDetailed works the same way as NIntegrateTanhSinh, but
+// returns an IntegrationResult and accepts options. MaxDepth is the number
+// of levels (12 by default), MaxEvaluations stops before a level whose
+// nodes would not fit, and MinWidth is compared with the step of the level
+// scaled to [a, b]. The first level takes up to 13 evaluations, so a
+// smaller MaxEvaluations evaluates nothing and returns a NaN value with an
+// infinite error estimate and IntegrationMaxEvaluations. The rule is
+// applied to [a, b] as a whole, so Subintervals is 1.
+func NIntegrateTanhSinhDetailed(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	if a == b {
+		return
+	}
+	if a > b {
+		detailed = NIntegrateTanhSinhDetailed(f, b, a, goalErrorAbs,
+			goalErrorRel, options)
+		detailed.Value = -detailed.Value
+		return
+	}
+	limits := options.withDefaults(0, tanhSinhMaxLevels)
+	if limits.MaxEvaluations != 0 &&
+		limits.MaxEvaluations < tanhSinhFirstNodes {
+		// Not even the nodes of the first level fit into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = IntegrationMaxEvaluations
+		return
+	}
+	f = countingFunction(f, &detailed.Evaluations)
+	var (
+		h             float64 = 1
+		sum           float64 = tanhSinhSum(f, a, b, h, 0, 1)
+		result        float64 = h * sum
+		errorEstimate float64 = math.Inf(1)
+		status        IntegrationStatus
+	)
+	for level := 1; ; level++ {
+		// A level evaluates two nodes for every odd multiple of h/2, and
+		// there are at most int(tanhSinhMaxT/h)+1 of them
+		var ok bool
+		ok, status = limits.allow(detailed.Evaluations,
+			2*int(tanhSinhMaxT/h)+2, level, (b-a)/2*h/2)
+		if !ok {
+			break
+		}
+		h /= 2
+		sum += tanhSinhSum(f, a, b, h, 1, 2)
+		previous := result
+		result = h * sum
+		errorEstimate = math.Abs(result - previous)
+		if !isFinite(result) {
+			status = IntegrationDivergent
+			break
+		}
+		if level >= tanhSinhMinLevels && integrationGoalsMet(result,
+			errorEstimate, goalErrorAbs, goalErrorRel) {
+			break
+		}
+	}
+	detailed.Value, detailed.ErrorEstimate = result, errorEstimate
+	detailed.Subintervals = 1
+	detailed.Status = status
+	return
+}
+
+// Parameters of the Romberg method
+const (
+	rombergDefaultLevels int = 20
+	rombergMinLevels     int = 4
+)
+
+// Computes row number level of the Romberg tableau from the previous row.
+// The trapezoid value of the new row only evaluates f at the midpoints of
+// the previous level, all other values are reused through previous[0].
+func rombergRow(f SingleVarFunction, a float64, b float64, level int,
+	previous []float64) (row []float64) {
+	row = make([]float64, level+1)
+	if level == 0 {
+		row[0] = (b - a) / 2 * (f(a) + f(b))
+		return
+	}
+	var (
+		points int     = 1 << uint(level-1)
+		h      float64 = (b - a) / float64(2*points)
+		sum    float64 = 0
+	)
+	for i := 0; i < points; i++ {
+		sum += f(a + float64(2*i+1)*h)
+	}
+	row[0] = previous[0]/2 + h*sum
+	factor := 1.0
+	for j := 1; j <= level; j++ {
+		factor *= 4
+		row[j] = row[j-1] + (row[j-1]-previous[j-1])/(factor-1)
+	}
+	return
+}
+
+// NIntegrateRombergTableau works the same way as NIntegrateRomberg, but also
+// returns the Romberg tableau. Row k of the tableau starts with the
+// trapezoid rule on 2^k subintervals, followed by k Richardson
+// extrapolations of it, so tableau[k][k] is the k-th Romberg estimate.
+// Looking at how the diagonal settles helps diagnose convergence.
+func NIntegrateRombergTableau(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64, maxLevels int) (result float64,
+	errorEstimate float64, tableau [][]float64) {
+	var detailed IntegrationResult
+	detailed, tableau = rombergIntegrate(f, a, b, goalErrorAbs, goalErrorRel,
+		&IntegrationOptions{MaxDepth: maxLevels})
+	return detailed.Value, detailed.ErrorEstimate, tableau
+}
+
+// Builds the Romberg tableau level by level until the targets are met or a
+// limit is reached.
+func rombergIntegrate(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult,
+	tableau [][]float64) {
+	limits := options.withDefaults(0, rombergDefaultLevels)
+	f = countingFunction(f, &detailed.Evaluations)
+	tableau = [][]float64{rombergRow(f, a, b, 0, nil)}
+	var (
+		result        float64 = tableau[0][0]
+		errorEstimate float64 = math.Inf(1)
+		status        IntegrationStatus
+	)
+	for level := 1; ; level++ {
+		var ok bool
+		ok, status = limits.allow(detailed.Evaluations, 1<<uint(level-1),
+			level, math.Ldexp(b-a, -level))
+		if !ok {
+			break
+		}
+		tableau = append(tableau, rombergRow(f, a, b, level,
+			tableau[level-1]))
+		previous := result
+		result = tableau[level][level]
+		errorEstimate = math.Abs(result - previous)
+		if !isFinite(result) {
+			status = IntegrationDivergent
+			break
+		}
+		if level >= rombergMinLevels && integrationGoalsMet(result,
+			errorEstimate, goalErrorAbs, goalErrorRel) {
+			break
+		}
+	}
+	detailed.Value, detailed.ErrorEstimate = result, errorEstimate
+	detailed.Subintervals = 1 << uint(len(tableau)-1)
+	detailed.Status = status
+	return
+}
+
+// NIntegrateRomberg attempts to find the numeric value of the integral of f
+// in the interval [a, b] using Romberg's method. The trapezoid rule is
+// applied with a step halved on each level, reusing all previous function
+// values, and the results are improved by Richardson extrapolation.
+// This converges very quickly for smooth integrands.
+// Levels are added until the difference between the last two diagonal
+// estimates is within both targets (goalErrorAbs and
+// goalErrorRel * |result|), or until maxLevels levels (2^maxLevels + 1
+// evaluations) have been used. A value of 0 for maxLevels selects a default.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateRomberg(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64, maxLevels int) (result float64,
+	errorEstimate float64) {
+	result, errorEstimate, _ = NIntegrateRombergTableau(f, a, b, goalErrorAbs,
+		goalErrorRel, maxLevels)
+	return
+}
+
+// NIntegrateRombergDetailed works the same way as NIntegrateRomberg, but
+// returns an IntegrationResult and accepts options. MaxDepth is the number
+// of levels (20 by default), MaxEvaluations stops before a level whose new
+// nodes would not fit, and MinWidth is compared with the trapezoid step.
+// Subintervals is the number of trapezoid panels of the last level.
+func NIntegrateRombergDetailed(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	detailed, _ = rombergIntegrate(f, a, b, goalErrorAbs, goalErrorRel,
+		options)
+	return
+}
+
+// Parameters of the nested Chebyshev rules (Clenshaw-Curtis and Fejer)
+const (
+	chebyshevDefaultLevels int = 12
+	chebyshevMinLevels     int = 3
+)
+
+// Returns cos(m*pi/n) for m = 0..2n-1, which covers every cosine needed by
+// the weights below after reducing the argument modulo 2*pi
+func cosineTable(n int) (table []float64) {
+	table = make([]float64, 2*n)
+	for m := range table {
+		table[m] = math.Cos(float64(m) * math.Pi / float64(n))
+	}
+	return
+}
+
+// Computes the Clenshaw-Curtis weights on [-1, 1] for the nodes
+// cos(k*pi/n), k = 0..n, where n is even.
+func clenshawCurtisWeights(n int) (weights []float64) {
+	weights = make([]float64, n+1)
+	cosines := cosineTable(n)
+	for k := 0; k <= n/2; k++ {
+		sum := 1.0
+		for j := 1; j <= n/2; j++ {
+			b := 2.0
+			if j == n/2 {
+				b = 1
+			}
+			sum -= b / float64(4*j*j-1) * cosines[(2*j*k)%(2*n)]
+		}
+		weights[k] = 2 * sum / float64(n)
+		if k == 0 {
+			weights[k] /= 2
+		}
+		weights[n-k] = weights[k]
+	}
+	return
+}
+
+// Computes the weights of Fejer's second rule on [-1, 1] for the nodes
+// cos(k*pi/n), k = 0..n, where n is even. The endpoint weights are zero.
+func fejerWeights(n int) (weights []float64) {
+	weights = make([]float64, n+1)
+	cosines := cosineTable(n)
+	for k := 1; k <= n/2; k++ {
+		sum := 0.0
+		for j := 1; j <= n/2; j++ {
+			// sin(m*pi/n) = cos((m - n/2)*pi/n)
+			m := ((2*j-1)*k - n/2 + 2*n) % (2 * n)
+			sum += cosines[m] / float64(2*j-1)
+		}
+		weights[k] = 4 * math.Sin(float64(k)*math.Pi/float64(n)) * sum /
+			float64(n)
+		weights[n-k] = weights[k]
+	}
+	return
+}
+
+// Integrates f in [a, b] by a rule on the nodes cos(k*pi/n), doubling n on
+// each level. The nodes of a level are every other node of the next one,
+// so all function values are kept and only the new nodes are evaluated.
+// If interior is set, the endpoints are never evaluated (their weights must
+// be zero).
+func nestedChebyshevIntegrate(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64, options *IntegrationOptions,
+	weightsFor func(n int) []float64,
+	interior bool) (detailed IntegrationResult) {
+	limits := options.withDefaults(0, chebyshevDefaultLevels)
+	f = countingFunction(f, &detailed.Evaluations)
+	var (
+		center        float64 = (a + b) / 2
+		halfLength    float64 = (b - a) / 2
+		n             int     = 2
+		fvalues       []float64
+		result        float64
+		errorEstimate float64
+		status        IntegrationStatus
+	)
+	node := func(k int, n int) float64 {
+		return center + halfLength*math.Cos(float64(k)*math.Pi/float64(n))
+	}
+	apply := func() (sum float64) {
+		for k, w := range weightsFor(n) {
+			sum += w * fvalues[k]
+		}
+		return sum * halfLength
+	}
+	fvalues = make([]float64, n+1)
+	for k := 0; k <= n; k++ {
+		if !interior || (k != 0 && k != n) {
+			fvalues[k] = f(node(k, n))
+		}
+	}
+	result = apply()
+	errorEstimate = math.Inf(1)
+	for level := 1; ; level++ {
+		// The new level evaluates the n midpoints of the current nodes
+		var ok bool
+		ok, status = limits.allow(detailed.Evaluations, n, level,
+			(b-a)/float64(2*n))
+		if !ok {
+			break
+		}
+		n *= 2
+		next := make([]float64, n+1)
+		for k := 0; k <= n; k++ {
+			if k%2 == 0 {
+				next[k] = fvalues[k/2]
+			} else {
+				next[k] = f(node(k, n))
+			}
+		}
+		fvalues = next
+		previous := result
+		result = apply()
+		errorEstimate = math.Abs(result - previous)
+		if !isFinite(result) {
+			status = IntegrationDivergent
+			break
+		}
+		if level >= chebyshevMinLevels && integrationGoalsMet(result,
+			errorEstimate, goalErrorAbs, goalErrorRel) {
+			break
+		}
+	}
+	detailed.Value, detailed.ErrorEstimate = result, errorEstimate
+	detailed.Subintervals = 1
+	detailed.Status = status
+	return
+}
+
+// NIntegrateClenshawCurtis attempts to find the numeric value of the
+// integral of f in the interval [a, b] using the Clenshaw-Curtis rule on
+// the Chebyshev points cos(k*pi/n). The number of points is doubled on each
+// level and every previous function value is reused, so the rule is nested
+// and a level's new points can be evaluated independently of each other.
+// Levels are added until the change between them is within both targets
+// (goalErrorAbs and goalErrorRel * |result|), or until maxLevels levels
+// (2^(maxLevels+1) + 1 evaluations) have been used. A value of 0 for
+// maxLevels selects a default.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateClenshawCurtis(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64, maxLevels int) (result float64,
+	errorEstimate float64) {
+	detailed := NIntegrateClenshawCurtisDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxDepth: maxLevels})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateClenshawCurtisDetailed works the same way as
+// NIntegrateClenshawCurtis, but returns an IntegrationResult and accepts
+// options. MaxDepth is the number of levels (12 by default),
+// MaxEvaluations stops before a level whose new nodes would not fit, and
+// MinWidth is compared with the average spacing of the nodes. The rule is
+// applied to [a, b] as a whole, so Subintervals is 1.
+func NIntegrateClenshawCurtisDetailed(f SingleVarFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	return nestedChebyshevIntegrate(f, a, b, goalErrorAbs, goalErrorRel,
+		options, clenshawCurtisWeights, false)
+}
+
+// NIntegrateFejer works the same way as NIntegrateClenshawCurtis, except
+// that it uses Fejer's second rule, which drops the two endpoints of the
+// Chebyshev points. It is suitable for functions undefined at a or b.
+func NIntegrateFejer(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64, maxLevels int) (result float64,
+	errorEstimate float64) {
+	detailed := NIntegrateFejerDetailed(f, a, b, goalErrorAbs, goalErrorRel,
+		&IntegrationOptions{MaxDepth: maxLevels})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateFejerDetailed works the same way as NIntegrateFejer, but
+// returns an IntegrationResult and accepts options, like
+// NIntegrateClenshawCurtisDetailed.
+func NIntegrateFejerDetailed(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	return nestedChebyshevIntegrate(f, a, b, goalErrorAbs, goalErrorRel,
+		options, fejerWeights, true)
+}
+
+// OscillatoryKernel selects the oscillating factor of the integrand for
+// NIntegrateOscillatory.
+type OscillatoryKernel int16
+
+const (
+	// OscillatorySin stands for the factor sin(omega*x)
+	OscillatorySin OscillatoryKernel = iota
+	// OscillatoryCos stands for the factor cos(omega*x)
+	OscillatoryCos
+)
+
+// Parameters of Filon's method. Below filonSmallTheta the closed form
+// coefficients suffer from cancellation and their series are used instead.
+const (
+	filonMaxLevels  int     = 16
+	filonMinLevels  int     = 3
+	filonSmallTheta float64 = 1.0 / 6
+)
+
+// Computes the Filon coefficients alpha, beta and gamma for theta = omega*h
+func filonCoefficients(theta float64) (alpha float64, beta float64,
+	gamma float64) {
+	if math.Abs(theta) < filonSmallTheta {
+		t2 := theta * theta
+		t3 := t2 * theta
+		alpha = t3 * (2.0/45 - t2*(2.0/315-t2*2.0/4725))
+		beta = 2.0/3 + t2*(2.0/15-t2*(4.0/105-t2*2.0/567))
+		gamma = 4.0/3 - t2*(2.0/15-t2*(1.0/210-t2/11340))
+		return
+	}
+	sin, cos := math.Sincos(theta)
+	t2 := theta * theta
+	t3 := t2 * theta
+	alpha = 1/theta + sin*cos/t2 - 2*sin*sin/t3
+	beta = 2 * ((1+cos*cos)/t2 - 2*sin*cos/t3)
+	gamma = 4 * (sin/t3 - cos/t2)
+	return
+}
+
+// Applies Filon's rule to the values fvalues of f at the 2n+1 equidistant
+// points of [a, b]. On each pair of panels f is replaced by a quadratic,
+// which is integrated against the kernel exactly.
+func filonRule(fvalues []float64, a float64, b float64, omega float64,
+	kernel OscillatoryKernel) (result float64) {
+	var (
+		last     int     = len(fvalues) - 1
+		h        float64 = (b - a) / float64(last)
+		evenSum  float64 = 0
+		oddSum   float64 = 0
+		kernelAt         = math.Cos
+	)
+	if kernel == OscillatorySin {
+		kernelAt = math.Sin
+	}
+	for i, fvalue := range fvalues {
+		term := fvalue * kernelAt(omega*(a+float64(i)*h))
+		if i%2 == 0 {
+			evenSum += term
+		} else {
+			oddSum += term
+		}
+	}
+	evenSum -= (fvalues[0]*kernelAt(omega*a) +
+		fvalues[last]*kernelAt(omega*b)) / 2
+	alpha, beta, gamma := filonCoefficients(omega * h)
+	var boundary float64
+	if kernel == OscillatorySin {
+		boundary = fvalues[0]*math.Cos(omega*a) -
+			fvalues[last]*math.Cos(omega*b)
+	} else {
+		boundary = fvalues[last]*math.Sin(omega*b) -
+			fvalues[0]*math.Sin(omega*a)
+	}
+	return h * (alpha*boundary + beta*evenSum + gamma*oddSum)
+}
+
+// NIntegrateOscillatory attempts to find the numeric value of the integral
+// of f(x)*sin(omega*x) or f(x)*cos(omega*x), as selected by kernel, in the
+// interval [a, b] using Filon's method. Only the smooth factor f is
+// interpolated, while the oscillating factor is integrated exactly, so the
+// number of evaluations depends on how smooth f is and not on omega.
+// The number of panels is doubled on each level, reusing all previous
+// function values, until the change between levels is within both targets
+// (goalErrorAbs and goalErrorRel * |result|). If this does not happen within
+// a fixed number of levels, the last estimate is returned.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateOscillatory(f SingleVarFunction, a float64, b float64,
+	omega float64, kernel OscillatoryKernel, goalErrorAbs float64,
+	goalErrorRel float64) (result float64, errorEstimate float64) {
+	detailed := NIntegrateOscillatoryDetailed(f, a, b, omega, kernel,
+		goalErrorAbs, goalErrorRel, nil)
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateOscillatoryDetailed works the same way as
+// NIntegrateOscillatory, but returns an IntegrationResult and accepts
+// options. MaxDepth is the number of levels (16 by default),
+// MaxEvaluations stops before a level whose new points would not fit, and
+// MinWidth is compared with the spacing of the points. The first estimate
+// costs 3 evaluations. Subintervals is the number of gaps between the
+// points of the last level.
+func NIntegrateOscillatoryDetailed(f SingleVarFunction, a float64,
+	b float64, omega float64, kernel OscillatoryKernel, goalErrorAbs float64,
+	goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	limits := options.withDefaults(0, filonMaxLevels)
+	f = countingFunction(f, &detailed.Evaluations)
+	var (
+		fvalues       []float64 = []float64{f(a), f((a + b) / 2), f(b)}
+		result        float64   = filonRule(fvalues, a, b, omega, kernel)
+		errorEstimate float64   = math.Inf(1)
+		status        IntegrationStatus
+	)
+	for level := 1; ; level++ {
+		// The new level evaluates the midpoints of the current panels
+		points := 2*len(fvalues) - 1
+		h := (b - a) / float64(points-1)
+		var ok bool
+		ok, status = limits.allow(detailed.Evaluations, len(fvalues)-1,
+			level, h)
+		if !ok {
+			break
+		}
+		next := make([]float64, points)
+		for i := range next {
+			if i%2 == 0 {
+				next[i] = fvalues[i/2]
+			} else {
+				next[i] = f(a + float64(i)*h)
+			}
+		}
+		fvalues = next
+		previous := result
+		result = filonRule(fvalues, a, b, omega, kernel)
+		errorEstimate = math.Abs(result - previous)
+		if !isFinite(result) {
+			status = IntegrationDivergent
+			break
+		}
+		if level >= filonMinLevels && integrationGoalsMet(result,
+			errorEstimate, goalErrorAbs, goalErrorRel) {
+			break
+		}
+	}
+	detailed.Value, detailed.ErrorEstimate = result, errorEstimate
+	detailed.Subintervals = len(fvalues) - 1
+	detailed.Status = status
+	return
+}
+
+// NIntegrateCauchy attempts to find the Cauchy principal value of the
+// integral of f(x)/(x-c) in the interval [a, b], where a < c < b.
+// The singularity is removed by folding the largest interval symmetric
+// around c onto itself: with d = min(c-a, b-c), the principal value equals
+// the integral of (f(c+t) - f(c-t))/t over [0, d], whose integrand stays
+// bounded as t approaches 0, plus the ordinary integral of f(x)/(x-c) over
+// what remains of [a, b]. Both parts are found by NIntegrateGaussKronrod
+// with half of goalErrorAbs and of maxEvaluations each (0 still means no
+// limit), and neither evaluates f at c. If c is the midpoint, there is no
+// remaining part and the folded one receives the whole goal and budget.
+// The folded integrand evaluates f twice, and the first estimate of a part
+// costs 15 evaluations of its integrand, so a maxEvaluations below 60 (30
+// for the midpoint) makes result NaN.
+// A `result` value of NaN means c is not inside (a, b).
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateCauchy(f SingleVarFunction, a float64, b float64, c float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result float64, errorEstimate float64) {
+	detailed := NIntegrateCauchyDetailed(f, a, b, c, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateCauchyDetailed works the same way as NIntegrateCauchy, but
+// returns an IntegrationResult and accepts options. Each part receives its
+// share of MaxEvaluations, counted in evaluations of f, and MaxDepth and
+// MinWidth as they are. The evaluations and subintervals of both parts are
+// added up, and the status is the worse of theirs.
+// A Value of NaN with IntegrationDivergent means c is not inside (a, b).
+func NIntegrateCauchyDetailed(f SingleVarFunction, a float64, b float64,
+	c float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	if !(a < c && c < b) {
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.NaN()
+		detailed.Status = IntegrationDivergent
+		return
+	}
+	var (
+		limits      IntegrationOptions = options.withDefaults(0, 0)
+		d           float64            = math.Min(c-a, b-c)
+		restA       float64            = c + d
+		restB       float64            = b
+		goalPerPart float64            = goalErrorAbs / 2
+		partLimits  IntegrationOptions = limits
+	)
+	if c-d > a {
+		restA, restB = a, c-d
+	}
+	if restA < restB {
+		partLimits.MaxEvaluations = limits.MaxEvaluations / 2
+	} else {
+		goalPerPart = goalErrorAbs
+	}
+	// A budget of 0 would mean no limit
+	foldedLimits := partLimits
+	foldedLimits.MaxEvaluations = partLimits.MaxEvaluations / 2
+	if limits.MaxEvaluations != 0 && foldedLimits.MaxEvaluations == 0 {
+		foldedLimits.MaxEvaluations = 1
+	}
+	if limits.MaxEvaluations != 0 && partLimits.MaxEvaluations == 0 {
+		partLimits.MaxEvaluations = 1
+	}
+	folded := func(t float64) float64 {
+		return (f(c+t) - f(c-t)) / t
+	}
+	detailed = NIntegrateGaussKronrodDetailed(folded, 0, d, goalPerPart,
+		goalErrorRel, &foldedLimits)
+	// The folded integrand evaluates f twice
+	detailed.Evaluations *= 2
+	if restA < restB {
+		regular := func(x float64) float64 {
+			return f(x) / (x - c)
+		}
+		rest := NIntegrateGaussKronrodDetailed(regular, restA, restB,
+			goalPerPart, goalErrorRel, &partLimits)
+		detailed.Value += rest.Value
+		detailed.ErrorEstimate += rest.ErrorEstimate
+		detailed.Evaluations += rest.Evaluations
+		detailed.Subintervals += rest.Subintervals
+		detailed.Status = worseStatus(detailed.Status, rest.Status)
+	}
+	return
+}
+
+// Parameters of the extrapolated integration
+const (
+	extrapolationDefaultLevels int = 10
+	extrapolationMinLevels     int = 3
+)
+
+// NIntegrateExtrapolated attempts to find the numeric value of the integral
+// of f in the interval [a, b] by extrapolating a sequence of Gauss-Kronrod
+// results. The interval starts as a single panel integrated by the 15-point
+// Kronrod rule. On each level, every panel whose error estimate exceeds its
+// share of both targets (goalErrorAbs and goalErrorRel * |result|, in
+// proportion to its width) is bisected, and the other panels keep their
+// values. The sequence of sums over the panels is accelerated by Wynn's
+// epsilon algorithm (see WynnEpsilon). Near an integrable singularity at an
+// endpoint only the panels next to it keep being bisected, and their sums
+// approach the limit slowly but regularly, which the extrapolation removes
+// in the manner of QUADPACK's QAGS. Levels are added until the
+// extrapolation error estimate is within both targets, until no panel
+// needs to be bisected, or until maxLevels levels have been used. A value
+// of 0 for maxLevels selects a default. A level costs 30 evaluations for
+// every bisected panel.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateExtrapolated(f SingleVarFunction, a float64, b float64,
+	goalErrorAbs float64, goalErrorRel float64, maxLevels int) (result float64,
+	errorEstimate float64) {
+	detailed := NIntegrateExtrapolatedDetailed(f, a, b, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxDepth: maxLevels})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateExtrapolatedDetailed works the same way as
+// NIntegrateExtrapolated, but returns an IntegrationResult and accepts
+// options. MaxDepth is the number of levels (10 by default),
+// MaxEvaluations stops before a level that would not fit, and MinWidth is
+// compared with the width of the narrowest new panel. The first level takes
+// 15 evaluations, so a smaller MaxEvaluations evaluates nothing and returns
+// a NaN value with an infinite error estimate and IntegrationMaxEvaluations.
+// Subintervals is the number of panels of the last level.
+func NIntegrateExtrapolatedDetailed(f SingleVarFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	limits := options.withDefaults(0, extrapolationDefaultLevels)
+	if limits.MaxEvaluations != 0 && limits.MaxEvaluations < kronrodNodeCount {
+		// Not even the first rule fits into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = IntegrationMaxEvaluations
+		return
+	}
+	first := gkSubinterval{a: a, b: b}
+	first.result, first.errorEstimate, _ = gaussKronrodRule(f, a, b)
+	var (
+		epsilon       WynnEpsilon
+		panels        []gkSubinterval = []gkSubinterval{first}
+		sum           float64         = first.result
+		result        float64
+		errorEstimate float64
+		status        IntegrationStatus
+	)
+	detailed.Evaluations = kronrodNodeCount
+	result, errorEstimate = epsilon.Add(sum)
+	for level := 1; isFinite(sum); level++ {
+		// A panel needs bisection if its error exceeds its share of the
+		// targets
+		var (
+			tolerance float64 = math.Min(goalErrorAbs,
+				goalErrorRel*math.Abs(sum))
+			split      int
+			width      float64 = math.Inf(1)
+			panelError float64
+			ok         bool
+		)
+		needsSplit := func(panel gkSubinterval) bool {
+			return panel.errorEstimate > tolerance*(panel.b-panel.a)/(b-a)
+		}
+		for _, panel := range panels {
+			panelError += panel.errorEstimate
+			if needsSplit(panel) {
+				split++
+				width = math.Min(width, math.Abs(panel.b-panel.a)/2)
+			}
+		}
+		if split == 0 {
+			// Every panel is within its share, so their sum is too
+			result, errorEstimate = sum, panelError
+			break
+		}
+		ok, status = limits.allow(detailed.Evaluations,
+			2*split*kronrodNodeCount, level, width)
+		if !ok {
+			break
+		}
+		next := make([]gkSubinterval, 0, len(panels)+split)
+		for _, panel := range panels {
+			if !needsSplit(panel) {
+				next = append(next, panel)
+				continue
+			}
+			mid := (panel.a + panel.b) / 2
+			left := gkSubinterval{a: panel.a, b: mid}
+			right := gkSubinterval{a: mid, b: panel.b}
+			left.result, left.errorEstimate, _ = gaussKronrodRule(f, left.a,
+				left.b)
+			right.result, right.errorEstimate, _ = gaussKronrodRule(f,
+				right.a, right.b)
+			next = append(next, left, right)
+		}
+		detailed.Evaluations += 2 * split * kronrodNodeCount
+		panels, sum = next, 0
+		for _, panel := range panels {
+			sum += panel.result
+		}
+		result, errorEstimate = epsilon.Add(sum)
+		if level >= extrapolationMinLevels && integrationGoalsMet(result,
+			errorEstimate, goalErrorAbs, goalErrorRel) {
+			break
+		}
+	}
+	if !isFinite(sum) {
+		status = IntegrationDivergent
+	}
+	detailed.Value, detailed.ErrorEstimate = result, errorEstimate
+	detailed.Subintervals = len(panels)
+	detailed.Status = status
+	return
 }
@@ -0,0 +1,303 @@
+// numint_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Integrands with a kink, a peak or a derivative singularity, along with the
+// bounds and the exact value of the integral
+var testIntegrals = []struct {
+	f     SingleVarFunction
+	a     float64
+	b     float64
+	exact float64
+}{
+	{math.Sin, 0, math.Pi, 2},
+	{math.Abs, -1, 2, 2.5},
+	{func(x float64) float64 { return 1 / (1e-4 + x*x) }, -1, 1,
+		200 * math.Atan(100)},
+	{math.Sqrt, 0, 1, 2.0 / 3},
+	{math.Exp, -3, 0, 1 - math.Exp(-3)},
+}
+
+// Tests that the adaptive Gauss-Kronrod method reaches the error goals and
+// that the error estimate bounds the actual error
+func TestGaussKronrodAdaptiveTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testIntegrals {
+		result, errorEstimate := NIntegrateGaussKronrod(tt.f, tt.a, tt.b,
+			goal, goal, 0)
+		if math.Abs(result-tt.exact) > goal*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Integral", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		if math.Abs(result-tt.exact) > errorEstimate {
+			t.Error("Integral", i, "has error estimate", errorEstimate,
+				"below the actual error", math.Abs(result-tt.exact))
+		}
+	}
+}
+
+// Tests that the evaluation budget is respected
+func TestGaussKronrodAdaptiveBudget(t *testing.T) {
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return 1 / math.Sqrt(math.Abs(x-0.3))
+	}
+	for _, budget := range []int{300, 14} {
+		calls = 0
+		result, _ := NIntegrateGaussKronrod(f, 0, 1, 0, 0, budget)
+		if calls > budget {
+			t.Error("Used", calls, "evaluations with a budget of", budget)
+		}
+		if budget < kronrodNodeCount && !math.IsNaN(result) {
+			t.Error("Produced", result, "with a budget of", budget)
+		}
+	}
+}
+
+// Integrals over infinite and semi-infinite intervals
+var testInfiniteIntegrals = []struct {
+	f     SingleVarFunction
+	a     float64
+	b     float64
+	exact float64
+}{
+	{func(x float64) float64 { return math.Exp(-x) }, 0, math.Inf(1), 1},
+	{math.Exp, math.Inf(-1), 1, math.E},
+	{func(x float64) float64 { return 1 / (1 + x*x) }, math.Inf(-1),
+		math.Inf(1), math.Pi},
+	{func(x float64) float64 { return 1 / (x * x) }, math.Inf(1), 2, -0.5},
+}
+
+// Tests the change of variables for infinite intervals
+func TestInfiniteIntervalTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testInfiniteIntegrals {
+		result, errorEstimate := NIntegrateInfinite(tt.f, tt.a, tt.b,
+			goal, goal, 0)
+		if math.Abs(result-tt.exact) > goal*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Integral", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		if math.Abs(result-tt.exact) > errorEstimate {
+			t.Error("Integral", i, "has error estimate", errorEstimate,
+				"below the actual error", math.Abs(result-tt.exact))
+		}
+	}
+}
+
+// Integrands with singularities at the endpoints
+var testSingularIntegrals = []struct {
+	f     SingleVarFunction
+	a     float64
+	b     float64
+	exact float64
+}{
+	{func(x float64) float64 { return 1 / math.Sqrt(x) }, 0, 1, 2},
+	{math.Log, 0, 1, -1},
+	{func(x float64) float64 { return 1 / math.Sqrt(1-x*x) }, -1, 1,
+		math.Pi},
+	{func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }, 0, 1, -4},
+	{math.Sin, 0, math.Pi, 2},
+}
+
+// Tests the tanh-sinh rule on endpoint singularities, with the bounds in
+// both orders. It also makes sure that the endpoints are never evaluated.
+func TestTanhSinhTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testSingularIntegrals {
+		f := func(x float64) float64 {
+			if x <= tt.a || x >= tt.b {
+				t.Fatal("Integral", i, "evaluated at", x)
+			}
+			return tt.f(x)
+		}
+		result, _ := NIntegrateTanhSinh(f, tt.a, tt.b, goal, goal)
+		if math.Abs(result-tt.exact) > 1e-7 {
+			t.Error("Integral", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		result, _ = NIntegrateTanhSinh(f, tt.b, tt.a, goal, goal)
+		if math.Abs(result+tt.exact) > 1e-7 {
+			t.Error("Reversed integral", i, "produced", result,
+				"instead of", -tt.exact)
+		}
+	}
+}
+
+// Tests that the Romberg method converges on every integral, kinks and
+// derivative singularities included, and that the tableau has the
+// structure of repeated Richardson extrapolation of the trapezoid rule
+func TestRombergTable(t *testing.T) {
+	const goal float64 = 1e-10
+	for i, tt := range testIntegrals {
+		result, _, tableau := NIntegrateRombergTableau(tt.f, tt.a, tt.b, goal,
+			goal, 0)
+		if math.Abs(result-tt.exact) > 1e-8*math.Max(1, math.Abs(tt.exact)) {
+			t.Error("Integral", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		last := len(tableau) - 1
+		if result != tableau[last][last] {
+			t.Error("Integral", i, "produced", result,
+				"instead of the last diagonal entry", tableau[last][last])
+		}
+		for k, row := range tableau {
+			if len(row) != k+1 {
+				t.Error("Integral", i, "has", len(row), "entries in row", k)
+				continue
+			}
+			// The first column is the trapezoid rule on 2^k panels
+			panels := 1 << uint(k)
+			h := (tt.b - tt.a) / float64(panels)
+			trapezoid := (tt.f(tt.a) + tt.f(tt.b)) / 2
+			for j := 1; j < panels; j++ {
+				trapezoid += tt.f(tt.a + float64(j)*h)
+			}
+			trapezoid *= h
+			if math.Abs(row[0]-trapezoid) > 1e-12*math.Max(1,
+				math.Abs(trapezoid)) {
+				t.Error("Integral", i, "has", row[0], "in row", k,
+					"instead of the trapezoid value", trapezoid)
+			}
+			// Every other column extrapolates the one before it
+			for j := 1; j <= k; j++ {
+				factor := math.Ldexp(1, 2*j) - 1
+				expected := row[j-1] + (row[j-1]-tableau[k-1][j-1])/factor
+				if math.Abs(row[j]-expected) > 1e-12*math.Max(1,
+					math.Abs(expected)) {
+					t.Error("Integral", i, "has", row[j], "at", k, j,
+						"instead of the extrapolation", expected)
+				}
+			}
+		}
+	}
+}
+
+// Tests the nested Chebyshev rules and the reuse of function values
+func TestClenshawCurtisFejerTable(t *testing.T) {
+	const goal float64 = 1e-10
+	integrators := []func(f SingleVarFunction, a float64, b float64,
+		goalErrorAbs float64, goalErrorRel float64, maxLevels int) (float64,
+		float64){
+		NIntegrateClenshawCurtis,
+		NIntegrateFejer,
+	}
+	for _, integrator := range integrators {
+		for i, tt := range testIntegrals {
+			seen := make(map[float64]bool)
+			f := func(x float64) float64 {
+				if seen[x] {
+					t.Error(getFunctionName(integrator), "evaluated", x,
+						"twice")
+				}
+				seen[x] = true
+				return tt.f(x)
+			}
+			result, _ := integrator(f, tt.a, tt.b, goal, goal, 0)
+			if math.Abs(result-tt.exact) > 1e-6*math.Max(1,
+				math.Abs(tt.exact)) {
+				t.Error(getFunctionName(integrator), "produced", result,
+					"instead of", tt.exact, "for integral", i)
+			}
+		}
+	}
+}
+
+// Tests Filon's method against the closed form of the integral of
+// e^x * e^(i*omega*x) in [0, 1] for slow and fast oscillations
+func TestOscillatoryExponential(t *testing.T) {
+	for _, omega := range []float64{0.5, 10, 1000, 1e5} {
+		exact := complex(math.E*math.Cos(omega)-1,
+			math.E*math.Sin(omega)) / complex(1, omega)
+		cosResult, _ := NIntegrateOscillatory(math.Exp, 0, 1, omega,
+			OscillatoryCos, 1e-10, 1e-10)
+		sinResult, _ := NIntegrateOscillatory(math.Exp, 0, 1, omega,
+			OscillatorySin, 1e-10, 1e-10)
+		if math.Abs(cosResult-real(exact)) > 1e-9 ||
+			math.Abs(sinResult-imag(exact)) > 1e-9 {
+			t.Error("Frequency", omega, "produced", cosResult, sinResult,
+				"instead of", real(exact), imag(exact))
+		}
+	}
+}
+
+// Principal value integrals of f(x)/(x-c) in [a, b]
+var testCauchyIntegrals = []struct {
+	f     SingleVarFunction
+	a     float64
+	b     float64
+	c     float64
+	exact float64
+}{
+	{func(float64) float64 { return 1 }, -1, 2, 0, math.Log(2)},
+	{func(float64) float64 { return 1 }, -3, 1, 0, -math.Log(3)},
+	{math.Exp, -1, 1, 0, 2.1145017507514569},
+	{func(x float64) float64 { return x * x }, 0, 2, 0.5,
+		3 + 0.25*math.Log(3)},
+}
+
+// Tests the principal value integrals, including their error estimates
+func TestCauchyTable(t *testing.T) {
+	const goal float64 = 1e-10
+	for i, tt := range testCauchyIntegrals {
+		result, errorEstimate := NIntegrateCauchy(tt.f, tt.a, tt.b, tt.c,
+			goal, goal, 0)
+		if math.Abs(result-tt.exact) > 1e-9 {
+			t.Error("Integral", i, "produced", result, "instead of",
+				tt.exact)
+		}
+		if math.Abs(result-tt.exact) > errorEstimate+1e-15 {
+			t.Error("Integral", i, "has error estimate", errorEstimate,
+				"below the actual error", math.Abs(result-tt.exact))
+		}
+	}
+	if result, _ := NIntegrateCauchy(math.Exp, 0, 1, 1, goal, goal,
+		0); !math.IsNaN(result) {
+		t.Error("Accepted a pole at the endpoint")
+	}
+	// Budgets count the evaluations of f, and too small ones evaluate
+	// nothing
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return 1 / math.Sqrt(x)
+	}
+	for _, c := range []float64{0.3, 0.5} {
+		for _, budget := range []int{20, 100, 1000} {
+			calls = 0
+			result, _ := NIntegrateCauchy(f, 0, 1, c, 0, 0, budget)
+			if calls > budget {
+				t.Error("Used", calls, "evaluations with a budget of", budget)
+			}
+			if budget < 30 && !math.IsNaN(result) {
+				t.Error("Produced", result, "with a budget of", budget)
+			}
+		}
+	}
+}
+
+// Tests the extrapolated integration on endpoint singularities. Only the
+// panels next to a singular endpoint may be bisected on each level.
+func TestExtrapolatedTable(t *testing.T) {
+	const goal float64 = 1e-10
+	for i, tt := range testSingularIntegrals {
+		result, errorEstimate := NIntegrateExtrapolated(tt.f, tt.a, tt.b,
+			goal, goal, 0)
+		if math.Abs(result-tt.exact) > 1e-9 ||
+			math.Abs(result-tt.exact) > errorEstimate+1e-15 {
+			t.Error("Integral", i, "produced", result, "with error estimate",
+				errorEstimate, "instead of", tt.exact)
+		}
+		detailed := NIntegrateExtrapolatedDetailed(tt.f, tt.a, tt.b, goal,
+			goal, nil)
+		if detailed.Evaluations > 15+2*30*extrapolationDefaultLevels {
+			t.Error("Integral", i, "used", detailed.Evaluations,
+				"evaluations")
+		}
+	}
+}
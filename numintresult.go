@@ -0,0 +1,213 @@
+// numintresult.go
+package gonumeth
+
+import (
+	"errors"
+	"math"
+)
+
+// IntegrationStatus tells how an integration ended.
+type IntegrationStatus int16
+
+const (
+	// IntegrationConverged means the error estimate is within both targets.
+	IntegrationConverged IntegrationStatus = iota
+	// IntegrationMaxEvaluations means the evaluation or level budget was
+	// spent before the targets were met.
+	IntegrationMaxEvaluations
+	// IntegrationRoundoff means rounding errors keep the estimate from
+	// improving any further, for example because the subintervals can not
+	// be split or the error is at the float64 resolution of the integral.
+	IntegrationRoundoff
+	// IntegrationDivergent means the integrand produced NaN or infinite
+	// values, which usually indicates a divergent integral.
+	IntegrationDivergent
+	// IntegrationMaxDepth means a subinterval would have needed more
+	// bisections than IntegrationOptions.MaxDepth allows.
+	IntegrationMaxDepth
+	// IntegrationMinWidth means a subinterval would have needed to be split
+	// below IntegrationOptions.MinWidth.
+	IntegrationMinWidth
+)
+
+// Errors returned by IntegrationResult.Err for each status but
+// IntegrationConverged.
+var (
+	ErrMaxEvaluations = errors.New("gonumeth: evaluation budget exhausted " +
+		"before the error targets were met")
+	ErrMaxDepth = errors.New("gonumeth: maximum subdivision depth reached " +
+		"before the error targets were met")
+	ErrMinWidth = errors.New("gonumeth: minimum subinterval width reached " +
+		"before the error targets were met")
+	ErrRoundoff = errors.New("gonumeth: roundoff error prevents reaching " +
+		"the error targets")
+	ErrDivergent = errors.New("gonumeth: the integrand is not finite or " +
+		"the integral diverges")
+)
+
+// String returns the name of the status.
+func (s IntegrationStatus) String() string {
+	switch s {
+	case IntegrationConverged:
+		return "converged"
+	case IntegrationMaxEvaluations:
+		return "max-evaluations"
+	case IntegrationMaxDepth:
+		return "max-depth"
+	case IntegrationMinWidth:
+		return "min-width"
+	case IntegrationRoundoff:
+		return "roundoff"
+	case IntegrationDivergent:
+		return "divergent"
+	}
+	return "unknown"
+}
+
+// Ranks the statuses from converged to divergent by how far they are from
+// the targets. The values of the constants can not be used for this, since
+// they keep their order of introduction.
+func (s IntegrationStatus) severity() int {
+	switch s {
+	case IntegrationConverged:
+		return 0
+	case IntegrationMaxEvaluations:
+		return 1
+	case IntegrationMaxDepth:
+		return 2
+	case IntegrationMinWidth:
+		return 3
+	case IntegrationRoundoff:
+		return 4
+	}
+	return 5
+}
+
+// Returns the more severe of two statuses, which is the status of an
+// integration made of parts.
+func worseStatus(s IntegrationStatus, t IntegrationStatus) IntegrationStatus {
+	if t.severity() > s.severity() {
+		return t
+	}
+	return s
+}
+
+// IntegrationResult holds the outcome of an integration: the estimate of
+// the integral (Value) and of its absolute error (ErrorEstimate), the
+// number of function evaluations spent, the number of subintervals (or
+// panels) of the final partition and a Status explaining why it stopped.
+// The estimate is kept whatever the status, so a caller may still use it
+// or decide on a fallback.
+type IntegrationResult struct {
+	Value         float64
+	ErrorEstimate float64
+	Evaluations   int
+	Subintervals  int
+	Status        IntegrationStatus
+}
+
+// Err returns nil if the integration converged, or the error matching its
+// status otherwise.
+func (r IntegrationResult) Err() error {
+	switch r.Status {
+	case IntegrationConverged:
+		return nil
+	case IntegrationMaxEvaluations:
+		return ErrMaxEvaluations
+	case IntegrationMaxDepth:
+		return ErrMaxDepth
+	case IntegrationMinWidth:
+		return ErrMinWidth
+	case IntegrationRoundoff:
+		return ErrRoundoff
+	}
+	return ErrDivergent
+}
+
+// IntegrationOptions limits the work of the detailed integrators. When a
+// limit is hit, the best estimate so far is returned with the status of
+// that limit. A nil *IntegrationOptions, or a zero field, selects the
+// default of the integrator, which is given in its documentation.
+//
+// MaxEvaluations bounds the number of function evaluations. MaxDepth bounds
+// the number of times the original interval may be bisected; for the
+// integrators that refine [a, b] as a whole it is the number of levels.
+// MinWidth is the narrowest subinterval (or step) that may be created.
+// Workers is the number of goroutines used by the parallel integrators,
+// where 0 selects runtime.GOMAXPROCS(0); the others ignore it.
+type IntegrationOptions struct {
+	MaxEvaluations int
+	MaxDepth       int
+	MinWidth       float64
+	Workers        int
+}
+
+// Returns a copy of options with its zero fields replaced by the given
+// defaults. The options may be nil.
+func (options *IntegrationOptions) withDefaults(maxEvaluations int,
+	maxDepth int) (limits IntegrationOptions) {
+	if options != nil {
+		limits = *options
+	}
+	if limits.MaxEvaluations == 0 {
+		limits.MaxEvaluations = maxEvaluations
+	}
+	if limits.MaxDepth == 0 {
+		limits.MaxDepth = maxDepth
+	}
+	return
+}
+
+// Checks whether a refinement that reaches depth with subintervals of the
+// given width and costs cost more evaluations on top of evaluations stays
+// within the limits. If not, the status of the violated limit is returned.
+// A zero limit is not enforced.
+func (limits IntegrationOptions) allow(evaluations int, cost int, depth int,
+	width float64) (ok bool, status IntegrationStatus) {
+	switch {
+	case limits.MaxEvaluations != 0 &&
+		evaluations+cost > limits.MaxEvaluations:
+		return false, IntegrationMaxEvaluations
+	case limits.MaxDepth != 0 && depth > limits.MaxDepth:
+		return false, IntegrationMaxDepth
+	case math.Abs(width) < limits.MinWidth:
+		return false, IntegrationMinWidth
+	}
+	return true, IntegrationConverged
+}
+
+// Checks whether errorEstimate is within both targets (goalErrorAbs and
+// goalErrorRel * |result|).
+func integrationGoalsMet(result float64, errorEstimate float64,
+	goalErrorAbs float64, goalErrorRel float64) bool {
+	return errorEstimate <= goalErrorAbs &&
+		errorEstimate <= math.Abs(result)*goalErrorRel
+}
+
+// Checks whether x is neither NaN nor infinite
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// Chooses the status of an integration that ran out of levels or budget
+// without converging.
+func unconvergedStatus(result float64,
+	errorEstimate float64) IntegrationStatus {
+	if !isFinite(result) || math.IsNaN(errorEstimate) {
+		return IntegrationDivergent
+	}
+	return IntegrationMaxEvaluations
+}
+
+// Wraps f so that every call increments *evaluations.
+func countingFunction(f SingleVarFunction,
+	evaluations *int) SingleVarFunction {
+	return func(x float64) float64 {
+		*evaluations++
+		return f(x)
+	}
+}
+
+// Error estimates below this multiple of the integral of |f| are dominated
+// by rounding and can not be improved by further refinement.
+const integrationRoundoffLevel float64 = 50 * machineEpsilon
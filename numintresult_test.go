@@ -0,0 +1,364 @@
+// numintresult_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests that a negative integral converges in the non-adaptive
+// Gauss-Kronrod method and that the evaluations are reused
+func TestGaussKronrodNonAdaptiveNegative(t *testing.T) {
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return -math.Exp(x)
+	}
+	detailed := NIntegrateGaussKronrodNonAdaptiveDetailed(f, 0, 1, 1e-8, 1e-8)
+	if detailed.Status != IntegrationConverged || detailed.Err() != nil {
+		t.Error("Status", detailed.Status, "instead of converged")
+	}
+	if math.Abs(detailed.Value-(1-math.E)) > 1e-12 {
+		t.Error("Produced", detailed.Value, "instead of", 1-math.E)
+	}
+	if calls != 15 || detailed.Evaluations != 15 {
+		t.Error("Used", calls, "evaluations, reported",
+			detailed.Evaluations, "instead of 15")
+	}
+	result, _ := NIntegrateGaussKronrodNonAdaptive(f, 0, 1, 1e-8, 1e-8)
+	if math.IsNaN(result) {
+		t.Error("The legacy function failed on a negative integral")
+	}
+}
+
+// Tests that a missed target keeps the estimate and reports the budget
+func TestIntegrationMaxEvaluations(t *testing.T) {
+	f := func(x float64) float64 { return 1 / math.Sqrt(math.Abs(x-0.3)) }
+	detailed := NIntegrateGaussKronrodDetailed(f, 0, 1, 1e-14, 1e-14,
+		&IntegrationOptions{MaxEvaluations: 300})
+	if detailed.Status != IntegrationMaxEvaluations ||
+		detailed.Err() != ErrMaxEvaluations {
+		t.Error("Status", detailed.Status, "instead of max-evaluations")
+	}
+	if detailed.Evaluations > 300 || detailed.Subintervals < 2 {
+		t.Error("Reported", detailed.Evaluations, "evaluations and",
+			detailed.Subintervals, "subintervals")
+	}
+	if math.IsNaN(detailed.Value) ||
+		math.Abs(detailed.Value-2*(math.Sqrt(0.3)+math.Sqrt(0.7))) > 0.1 {
+		t.Error("Produced", detailed.Value, "as the best estimate")
+	}
+	romberg := NIntegrateRombergDetailed(f, 0, 1, 1e-14, 1e-14,
+		&IntegrationOptions{MaxDepth: 5})
+	if romberg.Status != IntegrationMaxDepth ||
+		romberg.Evaluations != 33 || romberg.Subintervals != 32 {
+		t.Error("Romberg reported", romberg.Status, romberg.Evaluations,
+			"evaluations and", romberg.Subintervals, "subintervals")
+	}
+}
+
+// Tests that an integral of zero terminates without a budget
+func TestIntegrationRoundoff(t *testing.T) {
+	detailed := NIntegrateGaussKronrodDetailed(math.Sin, 0, 2*math.Pi,
+		1e-10, 1e-10, nil)
+	if detailed.Status != IntegrationRoundoff ||
+		detailed.Err() != ErrRoundoff {
+		t.Error("Status", detailed.Status, "instead of roundoff")
+	}
+	if math.Abs(detailed.Value) > 1e-14 {
+		t.Error("Produced", detailed.Value, "instead of 0")
+	}
+}
+
+// Tests that non-finite values of the integrand are reported
+func TestIntegrationDivergent(t *testing.T) {
+	f := func(x float64) float64 { return 1 / x }
+	results := []IntegrationResult{
+		NIntegrateSimpsonAdaptiveDetailed(f, 0, 1, 1e-8, 1e-8, nil),
+		NIntegrateRombergDetailed(f, 0, 1, 1e-8, 1e-8, nil),
+		NIntegrateClenshawCurtisDetailed(f, 0, 1, 1e-8, 1e-8, nil),
+	}
+	for i, detailed := range results {
+		if detailed.Status != IntegrationDivergent ||
+			detailed.Err() != ErrDivergent {
+			t.Error("Integrator", i, "has status", detailed.Status,
+				"instead of divergent")
+		}
+	}
+}
+
+// Tests the detailed variants against the table of integrals
+func TestDetailedTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testIntegrals {
+		results := []IntegrationResult{
+			NIntegrateGaussKronrodDetailed(tt.f, tt.a, tt.b, goal, goal, nil),
+			NIntegrateSimpsonAdaptiveDetailed(tt.f, tt.a, tt.b, goal, goal,
+				nil),
+		}
+		for j, detailed := range results {
+			// Next to the singular derivative of sqrt the relative target
+			// of Simpson's method is never met, so the depth limit ends it
+			if detailed.Status != IntegrationConverged &&
+				detailed.Status != IntegrationMaxDepth {
+				t.Error("Integral", i, "integrator", j, "has status",
+					detailed.Status)
+			}
+			if math.Abs(detailed.Value-tt.exact) > 1e-7 {
+				t.Error("Integral", i, "integrator", j, "produced",
+					detailed.Value, "instead of", tt.exact)
+			}
+			if detailed.Evaluations == 0 || detailed.Subintervals == 0 {
+				t.Error("Integral", i, "integrator", j, "reported",
+					detailed.Evaluations, "evaluations and",
+					detailed.Subintervals, "subintervals")
+			}
+		}
+	}
+}
+
+// Tests that the recursion of Simpson's method respects its limits and
+// still covers the whole interval
+func TestSimpsonAdaptiveLimits(t *testing.T) {
+	calls := 0
+	step := func(x float64) float64 {
+		calls++
+		if x < 1/math.Pi {
+			return 0
+		}
+		return 1
+	}
+	detailed := NIntegrateSimpsonAdaptiveDetailed(step, 0, 1, 0, 0,
+		&IntegrationOptions{MaxEvaluations: 200})
+	if detailed.Status != IntegrationMaxEvaluations || calls > 200 ||
+		detailed.Evaluations != calls {
+		t.Error("Status", detailed.Status, "after", calls, "calls, reported",
+			detailed.Evaluations)
+	}
+	if math.Abs(detailed.Value-(1-1/math.Pi)) > 1e-2 {
+		t.Error("Produced", detailed.Value, "instead of", 1-1/math.Pi)
+	}
+	detailed = NIntegrateSimpsonAdaptiveDetailed(step, 0, 1, 1e-12, 1e-12,
+		&IntegrationOptions{MinWidth: 1e-3})
+	if detailed.Status != IntegrationMinWidth ||
+		detailed.Err() != ErrMinWidth {
+		t.Error("Status", detailed.Status, "instead of min-width")
+	}
+	detailed = NIntegrateSimpsonAdaptiveDetailed(step, 0, 1, 1e-12, 1e-12,
+		&IntegrationOptions{MaxDepth: 8})
+	if detailed.Status != IntegrationMaxDepth ||
+		detailed.Err() != ErrMaxDepth {
+		t.Error("Status", detailed.Status, "instead of max-depth")
+	}
+	// An integral of zero must terminate under the default limits
+	detailed = NIntegrateSimpsonAdaptiveDetailed(math.Sin, 0, 2*math.Pi,
+		1e-10, 1e-10, nil)
+	if math.Abs(detailed.Value) > 1e-10 {
+		t.Error("Produced", detailed.Value, "with status", detailed.Status)
+	}
+}
+
+// Tests that the adaptive Gauss-Kronrod method stops at the depth, width and
+// evaluation limits, including a budget too small for the first rule
+func TestGaussKronrodLimits(t *testing.T) {
+	f := func(x float64) float64 { return 1 / math.Sqrt(math.Abs(x-0.3)) }
+	detailed := NIntegrateGaussKronrodDetailed(f, 0, 1, 1e-14, 1e-14,
+		&IntegrationOptions{MaxDepth: 6})
+	if detailed.Status != IntegrationMaxDepth {
+		t.Error("Status", detailed.Status, "instead of max-depth")
+	}
+	detailed = NIntegrateGaussKronrodDetailed(f, 0, 1, 1e-14, 1e-14,
+		&IntegrationOptions{MinWidth: 1e-4})
+	if detailed.Status != IntegrationMinWidth {
+		t.Error("Status", detailed.Status, "instead of min-width")
+	}
+	// A budget below the first rule evaluates nothing
+	calls := 0
+	counted := func(x float64) float64 {
+		calls++
+		return f(x)
+	}
+	detailed = NIntegrateGaussKronrodDetailed(counted, 0, 1, 1e-14, 1e-14,
+		&IntegrationOptions{MaxEvaluations: 5})
+	if calls != 0 || detailed.Evaluations != 0 ||
+		detailed.Status != IntegrationMaxEvaluations ||
+		!math.IsNaN(detailed.Value) {
+		t.Error("Produced", detailed.Value, "with status", detailed.Status,
+			"and", calls, "evaluations for a budget of 5")
+	}
+}
+
+// Tests that the statuses keep the values they were introduced with, and
+// that parts are combined into the most severe status
+func TestIntegrationStatusValues(t *testing.T) {
+	statuses := []IntegrationStatus{IntegrationConverged,
+		IntegrationMaxEvaluations, IntegrationRoundoff, IntegrationDivergent,
+		IntegrationMaxDepth, IntegrationMinWidth}
+	for value, status := range statuses {
+		if int(status) != value {
+			t.Error("Status", status, "has the value", int(status),
+				"instead of", value)
+		}
+	}
+	if status := worseStatus(IntegrationMinWidth,
+		IntegrationRoundoff); status != IntegrationRoundoff {
+		t.Error("Combined min-width and roundoff into", status)
+	}
+	if status := worseStatus(IntegrationDivergent,
+		IntegrationMaxDepth); status != IntegrationDivergent {
+		t.Error("Combined divergent and max-depth into", status)
+	}
+}
+
+// Tests that the detailed variants of the multidimensional, complex,
+// oscillatory, tanh-sinh and principal value integrators converge on smooth
+// integrands, count their evaluations and respect their budgets. Each
+// integrand is smooth for a width of 0 and has a peak of that width
+// otherwise.
+func TestDetailedBudgets(t *testing.T) {
+	var calls int
+	peak := func(width float64, x float64) float64 {
+		return width / (width*width + (x-0.3141)*(x-0.3141))
+	}
+	integrators := []struct {
+		name      string
+		exact     float64
+		integrate func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult
+	}{
+		{"Cauchy", math.Log(3), func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult {
+			f := func(x float64) float64 {
+				calls++
+				return 1 + peak(width, x)
+			}
+			return NIntegrateCauchyDetailed(f, 0, 1, 0.25, goal, goal,
+				options)
+		}},
+		{"Oscillatory", (1 - math.Cos(2)) / 2, func(width float64,
+			goal float64, options *IntegrationOptions) IntegrationResult {
+			f := func(x float64) float64 {
+				calls++
+				return 1 + peak(width, x)
+			}
+			return NIntegrateOscillatoryDetailed(f, 0, 1, 2,
+				OscillatorySin, goal, goal, options)
+		}},
+		{"Cubature", 0.25, func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult {
+			f := func(x []float64) float64 {
+				calls++
+				return x[0]*x[1] + peak(width, x[0])
+			}
+			return NIntegrateCubatureDetailed(f, []float64{0, 0},
+				[]float64{1, 1}, goal, goal, options)
+		}},
+		{"Simplex", 1.0 / 24, func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult {
+			f := func(x []float64) float64 {
+				calls++
+				return x[0]*x[1] + peak(width, x[0])
+			}
+			return NIntegrateSimplexDetailed(f, unitTriangle, goal, goal,
+				options)
+		}},
+		{"TanhSinh", 1, func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult {
+			f := func(x float64) float64 {
+				calls++
+				return 1 + peak(width, x)
+			}
+			return NIntegrateTanhSinhDetailed(f, 0, 1, goal, goal, options)
+		}},
+		{"Complex", math.Sin(1), func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult {
+			f := func(x float64) complex128 {
+				calls++
+				return complex(math.Cos(x)+peak(width, x), x)
+			}
+			_, detailed := NIntegrateComplexDetailed(f, 0, 1, goal, goal,
+				options)
+			return detailed
+		}},
+		{"Contour", 1.125 / 3, func(width float64, goal float64,
+			options *IntegrationOptions) IntegrationResult {
+			f := func(z complex128) complex128 {
+				calls++
+				return z*z + complex(width, 0)/(z-complex(0.3141, width))
+			}
+			_, detailed := NIntegrateContourDetailed(f,
+				LineSegmentContour(-1, 0.5), goal, goal, options)
+			return detailed
+		}},
+	}
+	for _, integrator := range integrators {
+		calls = 0
+		detailed := integrator.integrate(0, 1e-10, nil)
+		if detailed.Status != IntegrationConverged ||
+			math.Abs(detailed.Value-integrator.exact) > 1e-9 {
+			t.Error(integrator.name, "produced", detailed.Value,
+				"with status", detailed.Status, "instead of",
+				integrator.exact)
+		}
+		if detailed.Evaluations != calls {
+			t.Error(integrator.name, "made", calls, "calls, reported",
+				detailed.Evaluations)
+		}
+		for _, budget := range []int{10, 200} {
+			calls = 0
+			detailed = integrator.integrate(1e-4, 1e-15,
+				&IntegrationOptions{MaxEvaluations: budget})
+			if detailed.Status != IntegrationMaxEvaluations ||
+				calls > budget || detailed.Evaluations != calls {
+				t.Error(integrator.name, "has status", detailed.Status,
+					"after", calls, "calls with a budget of", budget)
+			}
+		}
+	}
+}
+
+// Tests that budgets below the cost of the first rule evaluate nothing and
+// report a NaN value
+func TestFirstRuleBudgets(t *testing.T) {
+	var calls int
+	f := func(x float64) float64 {
+		calls++
+		return math.Exp(x)
+	}
+	integrators := []struct {
+		name      string
+		budget    int
+		integrate func(options *IntegrationOptions) IntegrationResult
+	}{
+		{"TanhSinh", 12, func(options *IntegrationOptions) IntegrationResult {
+			return NIntegrateTanhSinhDetailed(f, 0, 1, 0, 0, options)
+		}},
+		{"SimpsonAdaptive", 4, func(
+			options *IntegrationOptions) IntegrationResult {
+			return NIntegrateSimpsonAdaptiveDetailed(f, 0, 1, 0, 0, options)
+		}},
+		{"SimpsonAdaptiveBatch", 4, func(
+			options *IntegrationOptions) IntegrationResult {
+			return NIntegrateSimpsonAdaptiveBatch(VectorizeFunction(f), 0, 1,
+				0, 0, options)
+		}},
+		{"Extrapolated", 14, func(
+			options *IntegrationOptions) IntegrationResult {
+			return NIntegrateExtrapolatedDetailed(f, 0, 1, 0, 0, options)
+		}},
+	}
+	for _, integrator := range integrators {
+		for budget := 1; budget <= integrator.budget; budget++ {
+			calls = 0
+			detailed := integrator.integrate(
+				&IntegrationOptions{MaxEvaluations: budget})
+			if calls != 0 || detailed.Evaluations != 0 ||
+				detailed.Status != IntegrationMaxEvaluations ||
+				!math.IsNaN(detailed.Value) {
+				t.Error(integrator.name, "produced", detailed.Value,
+					"with status", detailed.Status, "and", calls,
+					"evaluations for a budget of", budget)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package gonumeth
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Default parameters of the inverse Laplace transforms. They are tuned to
+// float64, where more terms only add rounding error.
+const (
+	talbotDefaultNodes   int     = 24
+	stehfestDefaultTerms int     = 14
+	deHoogDefaultDegree  int     = 16
+	deHoogPeriodScale    float64 = 2
+	deHoogTargetError    float64 = 1e-12
+)
+
+// NInverseLaplaceTalbot computes the inverse Laplace transform f(t) of F(s)
+// at a time t > 0 by the fixed Talbot method of Abate and Valko. The
+// Bromwich integral is taken along a deformed contour that wraps around
+// the negative real axis, where the integrand decays quickly, and is
+// evaluated by the trapezoid rule on n nodes. F must be analytic to the
+// right of and on the contour, which holds when its singularities lie on
+// or near the negative real axis, as for rational transfer functions with
+// real poles. A value of 0 for n selects a default.
+// A `result` value of NaN means t <= 0 or n < 0.
+func NInverseLaplaceTalbot(F ComplexFunction, t float64,
+	n int) (result float64) {
+	if !(t > 0) || n < 0 {
+		return math.NaN()
+	}
+	if n == 0 {
+		n = talbotDefaultNodes
+	}
+	r := 2 * float64(n) / (5 * t)
+	result = 0.5 * real(F(complex(r, 0))) * math.Exp(r*t)
+	for k := 1; k < n; k++ {
+		theta := float64(k) * math.Pi / float64(n)
+		cot := 1 / math.Tan(theta)
+		s := complex(r*theta*cot, r*theta)
+		sigma := theta + (theta*cot-1)*cot
+		result += real(cmplx.Exp(s*complex(t, 0)) * F(s) *
+			complex(1, sigma))
+	}
+	return result * r / float64(n)
+}
+
+// Computes the Gaver-Stehfest coefficients V_1..V_n for an even n
+func stehfestCoefficients(n int) (v []float64) {
+	half := n / 2
+	factorial := func(k int) float64 {
+		f := 1.0
+		for i := 2; i <= k; i++ {
+			f *= float64(i)
+		}
+		return f
+	}
+	v = make([]float64, n+1)
+	for k := 1; k <= n; k++ {
+		for j := (k + 1) / 2; j <= k && j <= half; j++ {
+			v[k] += math.Pow(float64(j), float64(half)) * factorial(2*j) /
+				(factorial(half-j) * factorial(j) * factorial(j-1) *
+					factorial(k-j) * factorial(2*j-k))
+		}
+		if (k+half)%2 == 1 {
+			v[k] = -v[k]
+		}
+	}
+	return
+}
+
+// NInverseLaplaceStehfest computes the inverse Laplace transform f(t) of
+// F(s) at a time t > 0 by the Gaver-Stehfest method, which only evaluates F
+// at the n real points k*ln(2)/t, k = 1..n. The imaginary part of F is
+// ignored there. The method suits smooth, non-oscillating f. Its
+// coefficients alternate and grow quickly with n, so in float64 n should
+// stay around 12 to 16. A value of 0 for n selects a default.
+// A `result` value of NaN means t <= 0, n < 0 or n is odd.
+func NInverseLaplaceStehfest(F ComplexFunction, t float64,
+	n int) (result float64) {
+	if n == 0 {
+		n = stehfestDefaultTerms
+	}
+	if !(t > 0) || n < 2 || n%2 == 1 {
+		return math.NaN()
+	}
+	v := stehfestCoefficients(n)
+	step := math.Ln2 / t
+	for k := 1; k <= n; k++ {
+		result += v[k] * real(F(complex(float64(k)*step, 0)))
+	}
+	return result * step
+}
+
+// NInverseLaplaceDeHoog computes the inverse Laplace transform f(t) of F(s)
+// at a time t > 0 by the method of de Hoog, Knight and Stokes. The
+// Bromwich integral is written as a Fourier series with period 2T, where
+// T = 2t, and the series is accelerated by a diagonal Pade approximation
+// obtained from the quotient-difference algorithm. This takes 2*degree+1
+// evaluations of F on the line Re(s) = gamma, to the right of all the
+// singularities of F when these lie in Re(s) <= 0. A value of 0 for degree
+// selects a default.
+// A `result` value of NaN means t <= 0 or degree < 0.
+func NInverseLaplaceDeHoog(F ComplexFunction, t float64,
+	degree int) (result float64) {
+	if !(t > 0) || degree < 0 {
+		return math.NaN()
+	}
+	if degree == 0 {
+		degree = deHoogDefaultDegree
+	}
+	var (
+		m     int     = degree
+		T     float64 = deHoogPeriodScale * t
+		gamma float64 = -math.Log(deHoogTargetError) / (2 * T)
+		a     []complex128
+	)
+	a = make([]complex128, 2*m+1)
+	for k := range a {
+		a[k] = F(complex(gamma, math.Pi*float64(k)/T))
+	}
+	a[0] /= 2
+	// The quotient-difference table, with q[r][i] and e[r][i]
+	q := make([][]complex128, m+1)
+	e := make([][]complex128, m+1)
+	e[0] = make([]complex128, 2*m+1)
+	q[1] = make([]complex128, 2*m)
+	for i := 0; i < 2*m; i++ {
+		q[1][i] = a[i+1] / a[i]
+	}
+	for r := 1; r <= m; r++ {
+		e[r] = make([]complex128, 2*(m-r)+1)
+		for i := range e[r] {
+			e[r][i] = q[r][i+1] - q[r][i] + e[r-1][i+1]
+		}
+		if r < m {
+			q[r+1] = make([]complex128, 2*(m-r))
+			for i := range q[r+1] {
+				q[r+1][i] = q[r][i+1] * e[r][i+1] / e[r][i]
+			}
+		}
+	}
+	// Coefficients of the continued fraction
+	d := make([]complex128, 2*m+1)
+	d[0] = a[0]
+	for r := 1; r <= m; r++ {
+		d[2*r-1] = -q[r][0]
+		d[2*r] = -e[r][0]
+	}
+	// Evaluation of the continued fraction by the forward recurrence,
+	// with an improved remainder for the last term
+	var (
+		z   complex128 = cmplx.Exp(complex(0, math.Pi*t/T))
+		A_1 complex128 = 0
+		A   complex128 = d[0]
+		B_1 complex128 = 1
+		B   complex128 = 1
+	)
+	for i := 1; i < 2*m; i++ {
+		A, A_1 = A+d[i]*z*A_1, A
+		B, B_1 = B+d[i]*z*B_1, B
+	}
+	h := (1 + (d[2*m-1]-d[2*m])*z) / 2
+	remainder := -h * (1 - cmplx.Sqrt(1+d[2*m]*z/(h*h)))
+	A = A + remainder*A_1
+	B = B + remainder*B_1
+	return math.Exp(gamma*t) / T * real(A/B)
+}
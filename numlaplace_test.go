@@ -0,0 +1,64 @@
+// numlaplace_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Transforms F(s) with their originals f(t), and whether f oscillates,
+// which neither the Stehfest nor, for large t, the Talbot method can handle
+var testLaplacePairs = []struct {
+	F           ComplexFunction
+	f           SingleVarFunction
+	oscillating bool
+}{
+	{func(s complex128) complex128 { return 1 / (s + 1) },
+		func(t float64) float64 { return math.Exp(-t) }, false},
+	{func(s complex128) complex128 { return 1 / (s * s) },
+		func(t float64) float64 { return t }, false},
+	{func(s complex128) complex128 { return 1 / (s * (s + 1) * (s + 2)) },
+		func(t float64) float64 {
+			return 0.5 - math.Exp(-t) + 0.5*math.Exp(-2*t)
+		}, false},
+	{func(s complex128) complex128 { return s / (s*s + 1) }, math.Cos, true},
+}
+
+// Tests the three inverse Laplace transforms at several times
+func TestInverseLaplaceTable(t *testing.T) {
+	for i, tt := range testLaplacePairs {
+		for _, time := range []float64{0.1, 1, 5} {
+			exact := tt.f(time)
+			if result := NInverseLaplaceTalbot(tt.F, time, 0); math.Abs(
+				result-exact) > 1e-10 {
+				t.Error("Talbot produced", result, "instead of", exact,
+					"for pair", i, "at", time)
+			}
+			if result := NInverseLaplaceDeHoog(tt.F, time, 0); math.Abs(
+				result-exact) > 1e-9 {
+				t.Error("De Hoog produced", result, "instead of", exact,
+					"for pair", i, "at", time)
+			}
+			if result := NInverseLaplaceStehfest(tt.F, time,
+				0); !tt.oscillating && math.Abs(result-exact) > 1e-4 {
+				t.Error("Stehfest produced", result, "instead of", exact,
+					"for pair", i, "at", time)
+			}
+		}
+	}
+	if !math.IsNaN(NInverseLaplaceStehfest(testLaplacePairs[0].F, 1, 7)) {
+		t.Error("Stehfest accepted an odd number of terms")
+	}
+	F := testLaplacePairs[0].F
+	for _, n := range []int{-1, -2, -3} {
+		if result := NInverseLaplaceTalbot(F, 1, n); !math.IsNaN(result) {
+			t.Error("Talbot produced", result, "for", n, "nodes")
+		}
+		if result := NInverseLaplaceStehfest(F, 1, n); !math.IsNaN(result) {
+			t.Error("Stehfest produced", result, "for", n, "terms")
+		}
+		if result := NInverseLaplaceDeHoog(F, 1, n); !math.IsNaN(result) {
+			t.Error("De Hoog produced", result, "for degree", n)
+		}
+	}
+}
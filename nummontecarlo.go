@@ -0,0 +1,362 @@
+package gonumeth
+
+import (
+	"math"
+	"math/rand"
+)
+
+// QuasiRandomSequence is implemented by low-discrepancy sequences. Next
+// fills point with the next point of the sequence in [0, 1)^d, where d is
+// the dimension of the sequence.
+type QuasiRandomSequence interface {
+	Dimension() int
+	Next(point []float64)
+}
+
+// Number of bits of the Sobol sequence, which allows for 2^32 points
+const sobolBits uint = 32
+
+// Primitive polynomials over GF(2) and initial direction numbers for the
+// Sobol sequence, one row per dimension after the first. The polynomial of
+// a row is x^degree + a_1 x^(degree-1) + ... + a_(degree-1) x + 1, where
+// the bits of coefficients hold a_1 to a_(degree-1). The rows follow the
+// table of Joe and Kuo.
+var sobolTable = []struct {
+	degree       uint
+	coefficients uint32
+	initial      []uint32
+}{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+	{7, 7, []uint32{1, 1, 3, 13, 7, 35, 63}},
+	{7, 8, []uint32{1, 3, 5, 9, 1, 25, 53}},
+	{7, 14, []uint32{1, 3, 1, 13, 9, 35, 107}},
+	{7, 19, []uint32{1, 3, 1, 5, 27, 61, 31}},
+	{7, 21, []uint32{1, 1, 5, 11, 19, 41, 61}},
+	{7, 28, []uint32{1, 3, 5, 3, 3, 13, 69}},
+	{7, 31, []uint32{1, 1, 7, 13, 1, 19, 1}},
+	{7, 32, []uint32{1, 3, 7, 5, 13, 19, 59}},
+	{7, 37, []uint32{1, 1, 3, 9, 25, 29, 41}},
+	{7, 41, []uint32{1, 3, 5, 13, 23, 1, 55}},
+	{7, 42, []uint32{1, 3, 7, 3, 13, 59, 17}},
+	{7, 50, []uint32{1, 3, 1, 3, 5, 53, 69}},
+	{7, 55, []uint32{1, 1, 5, 5, 23, 33, 13}},
+	{7, 56, []uint32{1, 1, 7, 7, 1, 61, 123}},
+	{7, 59, []uint32{1, 1, 7, 9, 13, 61, 49}},
+	{7, 62, []uint32{1, 3, 3, 5, 3, 55, 33}},
+	{8, 14, []uint32{1, 3, 1, 15, 31, 13, 49, 245}},
+	{8, 21, []uint32{1, 3, 5, 15, 31, 59, 63, 97}},
+	{8, 22, []uint32{1, 3, 1, 11, 11, 11, 77, 249}},
+}
+
+// SobolMaxDimension is the largest dimension supported by SobolSequence
+var SobolMaxDimension int = len(sobolTable) + 1
+
+// SobolSequence generates the Sobol low-discrepancy sequence in base 2.
+// Points are produced in Gray code order, so each one costs a single XOR per
+// dimension. The first point is the origin.
+type SobolSequence struct {
+	directions [][sobolBits]uint32
+	state      []uint32
+	index      uint32
+}
+
+// NewSobolSequence returns a Sobol sequence of dimension dim, positioned
+// at its first point. A value of nil is returned unless
+// 1 <= dim <= SobolMaxDimension.
+func NewSobolSequence(dim int) *SobolSequence {
+	if dim < 1 || dim > SobolMaxDimension {
+		return nil
+	}
+	s := &SobolSequence{directions: make([][sobolBits]uint32, dim),
+		state: make([]uint32, dim)}
+	for k := uint(0); k < sobolBits; k++ {
+		s.directions[0][k] = 1 << (sobolBits - 1 - k)
+	}
+	for d := 1; d < dim; d++ {
+		row := sobolTable[d-1]
+		v := &s.directions[d]
+		for k := uint(0); k < sobolBits; k++ {
+			if k < row.degree {
+				v[k] = row.initial[k] << (sobolBits - 1 - k)
+				continue
+			}
+			v[k] = v[k-row.degree] ^ (v[k-row.degree] >> row.degree)
+			for j := uint(1); j < row.degree; j++ {
+				if (row.coefficients>>(row.degree-1-j))&1 == 1 {
+					v[k] ^= v[k-j]
+				}
+			}
+		}
+	}
+	return s
+}
+
+// Dimension returns the dimension of the sequence
+func (s *SobolSequence) Dimension() int {
+	return len(s.state)
+}
+
+// Next fills point with the next point of the sequence
+func (s *SobolSequence) Next(point []float64) {
+	for d, x := range s.state {
+		point[d] = float64(x) / (1 << sobolBits)
+	}
+	// The next state differs in the direction of the lowest zero bit
+	var c uint
+	for i := s.index; i&1 == 1; i >>= 1 {
+		c++
+	}
+	if c < sobolBits {
+		for d := range s.state {
+			s.state[d] ^= s.directions[d][c]
+		}
+	}
+	s.index++
+}
+
+// HaltonSequence generates the Halton low-discrepancy sequence, whose
+// coordinate d is the radical inverse of the point index in the d-th prime
+// base. The first point is the origin. Its quality degrades for large
+// dimensions, where the bases are large.
+type HaltonSequence struct {
+	bases []int
+	index int
+}
+
+// NewHaltonSequence returns a Halton sequence of dimension dim, positioned
+// at its first point. A value of nil is returned for dim < 1.
+func NewHaltonSequence(dim int) *HaltonSequence {
+	if dim < 1 {
+		return nil
+	}
+	h := &HaltonSequence{bases: make([]int, 0, dim)}
+	for candidate := 2; len(h.bases) < dim; candidate++ {
+		prime := true
+		for _, p := range h.bases {
+			if p*p > candidate {
+				break
+			}
+			if candidate%p == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			h.bases = append(h.bases, candidate)
+		}
+	}
+	return h
+}
+
+// Dimension returns the dimension of the sequence
+func (h *HaltonSequence) Dimension() int {
+	return len(h.bases)
+}
+
+// Next fills point with the next point of the sequence
+func (h *HaltonSequence) Next(point []float64) {
+	for d, base := range h.bases {
+		var (
+			x     float64 = 0
+			scale float64 = 1 / float64(base)
+		)
+		for i := h.index; i > 0; i /= base {
+			x += float64(i%base) * scale
+			scale /= float64(base)
+		}
+		point[d] = x
+	}
+	h.index++
+}
+
+// Number of randomly shifted copies of the point set used by
+// NIntegrateQuasiMonteCarlo to estimate its error
+const qmcReplicates int = 10
+
+// Checks the bounds of a Monte Carlo integral and returns their dimension
+// and the volume of the box, or 0 if they are invalid.
+func monteCarloBox(a []float64, b []float64) (n int, volume float64) {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0, 0
+	}
+	volume = 1
+	for i := range a {
+		volume *= b[i] - a[i]
+	}
+	return len(a), volume
+}
+
+// Maps u in [0, 1)^n onto the box [a, b] and stores the result in point
+func mapToBox(u []float64, a []float64, b []float64, point []float64) {
+	for i := range u {
+		point[i] = a[i] + u[i]*(b[i]-a[i])
+	}
+}
+
+// NIntegrateMonteCarlo estimates the integral of f over the box
+// [a[0], b[0]] x ... x [a[n-1], b[n-1]] as the box volume times the mean
+// of f at samples uniformly random points drawn from rng. Seeding rng
+// makes the result reproducible.
+// The error estimate is one standard deviation of the result, based on the
+// sample variance of f, and shrinks as 1/sqrt(samples).
+// A `result` value of NaN means the bounds are invalid, samples < 2 or rng
+// is nil.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateMonteCarlo(f MultiVarScalarFunction, a []float64, b []float64,
+	samples int, rng *rand.Rand) (result float64, errorEstimate float64) {
+	n, volume := monteCarloBox(a, b)
+	if n == 0 || samples < 2 || rng == nil {
+		return math.NaN(), math.NaN()
+	}
+	var (
+		u     []float64 = make([]float64, n)
+		point []float64 = make([]float64, n)
+		mean  float64   = 0
+		m2    float64   = 0
+	)
+	for k := 1; k <= samples; k++ {
+		for i := range u {
+			u[i] = rng.Float64()
+		}
+		mapToBox(u, a, b, point)
+		// Welford's update of the mean and the sum of squared deviations
+		value := f(point)
+		delta := value - mean
+		mean += delta / float64(k)
+		m2 += delta * (value - mean)
+	}
+	variance := m2 / float64(samples-1)
+	return volume * mean, math.Abs(volume) * math.Sqrt(variance/
+		float64(samples))
+}
+
+// NIntegrateMonteCarloStratified works the same way as NIntegrateMonteCarlo,
+// except that the box is split into strataPerDim^n equal cells and
+// samplesPerStratum random points are drawn in each of them. This removes
+// the variance between the cells from the result. The number of cells
+// grows exponentially with the dimension, so for high dimensions
+// strataPerDim should be small, or NIntegrateQuasiMonteCarlo be used.
+// A `result` value of NaN means the bounds are invalid, strataPerDim < 1,
+// samplesPerStratum < 2, rng is nil or there are too many cells.
+func NIntegrateMonteCarloStratified(f MultiVarScalarFunction, a []float64,
+	b []float64, strataPerDim int, samplesPerStratum int,
+	rng *rand.Rand) (result float64, errorEstimate float64) {
+	n, volume := monteCarloBox(a, b)
+	if n == 0 || strataPerDim < 1 || samplesPerStratum < 2 || rng == nil {
+		return math.NaN(), math.NaN()
+	}
+	cells := 1
+	for i := 0; i < n; i++ {
+		if cells > math.MaxInt32/strataPerDim {
+			return math.NaN(), math.NaN()
+		}
+		cells *= strataPerDim
+	}
+	var (
+		u          []float64 = make([]float64, n)
+		point      []float64 = make([]float64, n)
+		cell       []int     = make([]int, n)
+		cellVolume float64   = volume / float64(cells)
+		variance   float64   = 0
+	)
+	for c := 0; c < cells; c++ {
+		// The digits of c in base strataPerDim index the cell
+		for i, rest := 0, c; i < n; i, rest = i+1, rest/strataPerDim {
+			cell[i] = rest % strataPerDim
+		}
+		var mean, m2 float64
+		for k := 1; k <= samplesPerStratum; k++ {
+			for i := range u {
+				u[i] = (float64(cell[i]) + rng.Float64()) /
+					float64(strataPerDim)
+			}
+			mapToBox(u, a, b, point)
+			value := f(point)
+			delta := value - mean
+			mean += delta / float64(k)
+			m2 += delta * (value - mean)
+		}
+		result += cellVolume * mean
+		variance += cellVolume * cellVolume * m2 /
+			float64(samplesPerStratum-1) / float64(samplesPerStratum)
+	}
+	errorEstimate = math.Sqrt(variance)
+	return
+}
+
+// NIntegrateQuasiMonteCarlo estimates the integral of f over the box
+// [a[0], b[0]] x ... x [a[n-1], b[n-1]] using the next samples points of a
+// low-discrepancy sequence, such as a SobolSequence or a HaltonSequence of
+// dimension n. The error of such points typically falls close to
+// 1/samples, much faster than for random points.
+// To obtain a statistical error estimate, the point set is randomized by
+// a fixed number of random shifts (modulo 1) drawn from rng, and the shifted
+// estimates are averaged. The error estimate is one standard deviation of
+// the average. This costs samples evaluations per shift.
+// A `result` value of NaN means the bounds are invalid, samples < 1, rng is
+// nil or the dimension of sequence is not n.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateQuasiMonteCarlo(f MultiVarScalarFunction, a []float64,
+	b []float64, samples int, sequence QuasiRandomSequence,
+	rng *rand.Rand) (result float64, errorEstimate float64) {
+	n, volume := monteCarloBox(a, b)
+	if n == 0 || samples < 1 || rng == nil || sequence == nil ||
+		sequence.Dimension() != n {
+		return math.NaN(), math.NaN()
+	}
+	var (
+		points [][]float64 = make([][]float64, samples)
+		shift  []float64   = make([]float64, n)
+		u      []float64   = make([]float64, n)
+		point  []float64   = make([]float64, n)
+		mean   float64     = 0
+		m2     float64     = 0
+	)
+	for k := range points {
+		points[k] = make([]float64, n)
+		sequence.Next(points[k])
+	}
+	for r := 1; r <= qmcReplicates; r++ {
+		for i := range shift {
+			shift[i] = rng.Float64()
+		}
+		sum := 0.0
+		for _, p := range points {
+			for i := range u {
+				u[i] = p[i] + shift[i]
+				if u[i] >= 1 {
+					u[i]--
+				}
+			}
+			mapToBox(u, a, b, point)
+			sum += f(point)
+		}
+		estimate := volume * sum / float64(samples)
+		delta := estimate - mean
+		mean += delta / float64(r)
+		m2 += delta * (estimate - mean)
+	}
+	variance := m2 / float64(qmcReplicates-1)
+	return mean, math.Sqrt(variance / float64(qmcReplicates))
+}
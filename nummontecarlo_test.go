@@ -0,0 +1,103 @@
+// nummontecarlo_test.go
+package gonumeth
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// A smooth product integrand on [0, 1]^n whose integral is 1
+func productIntegrand(x []float64) float64 {
+	product := 1.0
+	for i, xi := range x {
+		product *= 1 + (xi-0.5)/float64(i+1)
+	}
+	return product
+}
+
+// Tests the first points of the Sobol sequence in three dimensions
+func TestSobolFirstPoints(t *testing.T) {
+	expected := [][]float64{{0, 0, 0}, {0.5, 0.5, 0.5}, {0.75, 0.25, 0.25},
+		{0.25, 0.75, 0.75}, {0.375, 0.375, 0.625}, {0.875, 0.875, 0.125}}
+	s := NewSobolSequence(3)
+	point := make([]float64, 3)
+	for _, e := range expected {
+		s.Next(point)
+		for i := range e {
+			if point[i] != e[i] {
+				t.Error("Produced", point, "instead of", e)
+				break
+			}
+		}
+	}
+	if NewSobolSequence(SobolMaxDimension+1) != nil {
+		t.Error("Accepted a dimension above", SobolMaxDimension)
+	}
+}
+
+// Tests that every row of the Sobol table holds a primitive polynomial and
+// valid initial direction numbers
+func TestSobolTable(t *testing.T) {
+	for d, row := range sobolTable {
+		for k, m := range row.initial {
+			if m%2 == 0 || m >= 1<<uint(k+1) {
+				t.Error("Row", d, "has invalid direction number", m)
+			}
+		}
+		// x has order 2^degree - 1 modulo a primitive polynomial
+		var (
+			polynomial uint32 = 1<<row.degree | row.coefficients<<1 | 1
+			order      uint32 = 1<<row.degree - 1
+			x          uint32 = 1
+			k          uint32
+		)
+		for k = 1; k <= order; k++ {
+			x <<= 1
+			if x>>row.degree&1 == 1 {
+				x ^= polynomial
+			}
+			if x == 1 {
+				break
+			}
+		}
+		if k != order {
+			t.Error("Row", d, "has a polynomial that is not primitive")
+		}
+	}
+}
+
+// Tests all Monte Carlo integrators against their error estimates
+func TestMonteCarloTable(t *testing.T) {
+	const n int = 20
+	a, b := unitBox(n)
+	rng := rand.New(rand.NewSource(42))
+	var results, errorEstimates [4]float64
+	results[0], errorEstimates[0] = NIntegrateMonteCarlo(productIntegrand, a,
+		b, 20000, rng)
+	results[1], errorEstimates[1] = NIntegrateMonteCarloStratified(
+		productIntegrand, a[:4], b[:4], 3, 20, rng)
+	results[2], errorEstimates[2] = NIntegrateQuasiMonteCarlo(
+		productIntegrand, a, b, 1024, NewSobolSequence(n), rng)
+	results[3], errorEstimates[3] = NIntegrateQuasiMonteCarlo(
+		productIntegrand, a, b, 1024, NewHaltonSequence(n), rng)
+	for i := range results {
+		if !(errorEstimates[i] > 0) ||
+			math.Abs(results[i]-1) > 5*errorEstimates[i] {
+			t.Error("Integrator", i, "produced", results[i],
+				"with error estimate", errorEstimates[i])
+		}
+	}
+}
+
+// Tests that a seeded generator gives reproducible results
+func TestMonteCarloReproducible(t *testing.T) {
+	a, b := unitBox(5)
+	first, _ := NIntegrateMonteCarlo(productIntegrand, a, b, 100,
+		rand.New(rand.NewSource(7)))
+	second, _ := NIntegrateMonteCarlo(productIntegrand, a, b, 100,
+		rand.New(rand.NewSource(7)))
+	if first != second {
+		t.Error("Produced", first, "and", second, "with the same seed")
+	}
+}
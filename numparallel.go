@@ -0,0 +1,126 @@
+// numparallel.go
+package gonumeth
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Parameters of the parallel integrators. They fix the work done on each
+// round independently of the number of workers, so the results depend
+// neither on the workers nor on the scheduling.
+const (
+	parallelGaussKronrodBatch int = 16
+	parallelSimpsonPanels     int = 16
+	parallelSimpsonPanelDepth int = 4 // 2^4 panels
+)
+
+// Calls do(i) for i = 0..jobs-1 on up to workers goroutines and waits for
+// all the calls to return. A value of 0 for workers selects
+// runtime.GOMAXPROCS(0).
+func parallelFor(workers int, jobs int, do func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > jobs {
+		workers = jobs
+	}
+	var (
+		wg   sync.WaitGroup
+		next chan int = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				do(i)
+			}
+		}()
+	}
+	for i := 0; i < jobs; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// NIntegrateGaussKronrodParallel works the same way as
+// NIntegrateGaussKronrodDetailed, but bisects several subintervals per
+// round and evaluates the rules on their halves concurrently on
+// options.Workers goroutines, so f must be safe for concurrent use.
+// A round takes the subintervals with the largest error estimates, up to
+// 16 of them, until the rest of the error would be within the targets.
+// The halves are merged in a fixed order, so the result is the same for
+// any number of workers and any scheduling, though it may differ slightly
+// from the serial one. This pays off when f is expensive to evaluate.
+func NIntegrateGaussKronrodParallel(f SingleVarFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	limits := options.withDefaults(0, 0)
+	evaluate := func(intervals []gkSubinterval) {
+		parallelFor(limits.Workers, len(intervals), func(i int) {
+			s := &intervals[i]
+			s.result, s.errorEstimate, s.absResult = gaussKronrodRule(f, s.a,
+				s.b)
+		})
+	}
+	detailed, _ = gaussKronrodAdaptive(evaluate, a, b, goalErrorAbs,
+		goalErrorRel, limits, parallelGaussKronrodBatch)
+	return
+}
+
+// NIntegrateSimpsonAdaptiveParallel works the same way as
+// NIntegrateSimpsonAdaptiveDetailed, but splits [a, b] into 16 equal panels
+// and integrates them concurrently on options.Workers goroutines, so f must
+// be safe for concurrent use. Each panel gets 1/16 of goalErrorAbs and of
+// the evaluation budget, and MaxDepth counts the 4 bisections that create
+// the panels. The panels are summed in order, so the result is the same
+// for any number of workers and any scheduling. The status is the worst
+// one among the panels. The first estimates of the panels cost 80
+// evaluations, so a smaller MaxEvaluations evaluates nothing and returns a
+// NaN value with an infinite error estimate and IntegrationMaxEvaluations.
+func NIntegrateSimpsonAdaptiveParallel(f SingleVarFunction, a float64,
+	b float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	limits := options.withDefaults(simpsonDefaultMaxEvaluations,
+		simpsonDefaultMaxDepth)
+	if limits.MaxEvaluations < parallelSimpsonPanels*5 {
+		// Not even the first estimates of the panels fit into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = IntegrationMaxEvaluations
+		return
+	}
+	panelOptions := IntegrationOptions{
+		MaxEvaluations: limits.MaxEvaluations / parallelSimpsonPanels,
+		MaxDepth:       limits.MaxDepth - parallelSimpsonPanelDepth,
+		MinWidth:       limits.MinWidth,
+	}
+	if panelOptions.MaxDepth <= 0 {
+		// The panels may not be split at all; 0 would select the default
+		panelOptions.MaxDepth = -1
+	}
+	var (
+		h      float64             = (b - a) / float64(parallelSimpsonPanels)
+		panels []IntegrationResult = make([]IntegrationResult,
+			parallelSimpsonPanels)
+	)
+	parallelFor(limits.Workers, parallelSimpsonPanels, func(i int) {
+		left, right := a+float64(i)*h, a+float64(i+1)*h
+		if i == parallelSimpsonPanels-1 {
+			right = b
+		}
+		panels[i] = NIntegrateSimpsonAdaptiveDetailed(f, left, right,
+			goalErrorAbs/float64(parallelSimpsonPanels), goalErrorRel,
+			&panelOptions)
+	})
+	for _, panel := range panels {
+		detailed.Value += panel.Value
+		detailed.ErrorEstimate += panel.ErrorEstimate
+		detailed.Evaluations += panel.Evaluations
+		detailed.Subintervals += panel.Subintervals
+		detailed.Status = worseStatus(detailed.Status, panel.Status)
+	}
+	return
+}
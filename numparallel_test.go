@@ -0,0 +1,92 @@
+// numparallel_test.go
+package gonumeth
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+// Tests that the parallel Gauss-Kronrod method reaches the error goals and
+// gives identical results for any number of workers
+func TestGaussKronrodParallelTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testIntegrals {
+		serial := NIntegrateGaussKronrodParallel(tt.f, tt.a, tt.b, goal, goal,
+			&IntegrationOptions{Workers: 1})
+		if serial.Status != IntegrationConverged ||
+			math.Abs(serial.Value-tt.exact) > goal*math.Max(1,
+				math.Abs(tt.exact)) {
+			t.Error("Integral", i, "produced", serial.Value, "with status",
+				serial.Status, "instead of", tt.exact)
+		}
+		for _, workers := range []int{2, 7, 0} {
+			parallel := NIntegrateGaussKronrodParallel(tt.f, tt.a, tt.b,
+				goal, goal, &IntegrationOptions{Workers: workers})
+			if parallel != serial {
+				t.Error("Integral", i, "with", workers, "workers gave",
+					parallel, "instead of", serial)
+			}
+		}
+	}
+}
+
+// Tests that the parallel Gauss-Kronrod method respects the evaluation
+// budget and counts the evaluations of all workers
+func TestGaussKronrodParallelBudget(t *testing.T) {
+	var calls int64
+	f := func(x float64) float64 {
+		atomic.AddInt64(&calls, 1)
+		return 1 / math.Sqrt(math.Abs(x-0.3))
+	}
+	detailed := NIntegrateGaussKronrodParallel(f, 0, 1, 1e-14, 1e-14,
+		&IntegrationOptions{MaxEvaluations: 1000, Workers: 4})
+	if detailed.Status != IntegrationMaxEvaluations || calls > 1000 ||
+		int64(detailed.Evaluations) != calls {
+		t.Error("Status", detailed.Status, "after", calls, "calls, reported",
+			detailed.Evaluations)
+	}
+}
+
+// Tests that the parallel Simpson's method gives identical results for any
+// number of workers
+func TestSimpsonAdaptiveParallelTable(t *testing.T) {
+	const goal float64 = 1e-9
+	for i, tt := range testIntegrals {
+		serial := NIntegrateSimpsonAdaptiveParallel(tt.f, tt.a, tt.b, goal,
+			goal, &IntegrationOptions{Workers: 1})
+		if math.Abs(serial.Value-tt.exact) > 1e-7 {
+			t.Error("Integral", i, "produced", serial.Value, "instead of",
+				tt.exact)
+		}
+		parallel := NIntegrateSimpsonAdaptiveParallel(tt.f, tt.a, tt.b, goal,
+			goal, &IntegrationOptions{Workers: 5})
+		if parallel != serial {
+			t.Error("Integral", i, "with 5 workers gave", parallel,
+				"instead of", serial)
+		}
+	}
+}
+
+// Tests that the parallel Simpson's method respects small evaluation
+// budgets, including those too small for the first estimates of the panels
+func TestSimpsonAdaptiveParallelBudget(t *testing.T) {
+	var calls int64
+	f := func(x float64) float64 {
+		atomic.AddInt64(&calls, 1)
+		return 1 / math.Sqrt(math.Abs(x-0.3))
+	}
+	for _, budget := range []int{15, 50, 80, 100, 1000} {
+		calls = 0
+		detailed := NIntegrateSimpsonAdaptiveParallel(f, 0, 1, 1e-14, 1e-14,
+			&IntegrationOptions{MaxEvaluations: budget, Workers: 4})
+		if detailed.Status != IntegrationMaxEvaluations ||
+			calls > int64(budget) || int64(detailed.Evaluations) != calls {
+			t.Error("Status", detailed.Status, "after", calls,
+				"calls with a budget of", budget)
+		}
+		if budget < 80 && (calls != 0 || !math.IsNaN(detailed.Value)) {
+			t.Error("Produced", detailed.Value, "with a budget of", budget)
+		}
+	}
+}
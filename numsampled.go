@@ -0,0 +1,80 @@
+package gonumeth
+
+import "math"
+
+// Checks that the samples (x[i], y[i]) can be integrated: the slices must
+// have the same length, at least two elements and strictly increasing x.
+func validSamples(x []float64, y []float64) bool {
+	if len(x) != len(y) || len(x) < 2 {
+		return false
+	}
+	for i := 1; i < len(x); i++ {
+		if !(x[i] > x[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NIntegrateSamplesTrapezoid finds the numeric value of the integral of the
+// function sampled as y[i] = f(x[i]) in [x[0], x[n-1]] using the trapezoid
+// rule. The points x must be strictly increasing but need not be equally
+// spaced.
+// A `result` value of NaN means the samples are invalid.
+func NIntegrateSamplesTrapezoid(x []float64, y []float64) (result float64) {
+	if !validSamples(x, y) {
+		return math.NaN()
+	}
+	for i := 1; i < len(x); i++ {
+		result += (x[i] - x[i-1]) * (y[i] + y[i-1]) / 2
+	}
+	return
+}
+
+// NIntegrateSamplesSimpson works the same way as NIntegrateSamplesTrapezoid,
+// except that it uses Simpson's rule, generalized to unequal spacing. Each
+// pair of consecutive intervals is integrated by the parabola through its
+// three samples. For an odd number of intervals, the last one is integrated
+// by the parabola through the last three samples. Two samples fall back to
+// the trapezoid rule.
+// A `result` value of NaN means the samples are invalid.
+func NIntegrateSamplesSimpson(x []float64, y []float64) (result float64) {
+	if !validSamples(x, y) {
+		return math.NaN()
+	}
+	intervals := len(x) - 1
+	if intervals == 1 {
+		return NIntegrateSamplesTrapezoid(x, y)
+	}
+	for i := 0; i+2 <= intervals; i += 2 {
+		h0, h1 := x[i+1]-x[i], x[i+2]-x[i+1]
+		result += (h0 + h1) / 6 * ((2-h1/h0)*y[i] +
+			(h0+h1)*(h0+h1)/(h0*h1)*y[i+1] + (2-h0/h1)*y[i+2])
+	}
+	if intervals%2 == 1 {
+		n := intervals
+		h0, h1 := x[n-1]-x[n-2], x[n]-x[n-1]
+		result += (2*h1*h1+3*h0*h1)/(6*(h0+h1))*y[n] +
+			(h1*h1+3*h0*h1)/(6*h0)*y[n-1] -
+			h1*h1*h1/(6*h0*(h0+h1))*y[n-2]
+	}
+	return
+}
+
+// NIntegrateSamplesCumulative returns the running integral of the function
+// sampled as y[i] = f(x[i]): element i of the result is the integral in
+// [x[0], x[i]] by the trapezoid rule, so the first element is 0 and the
+// last one equals NIntegrateSamplesTrapezoid(x, y). The points x must be
+// strictly increasing but need not be equally spaced.
+// A value of nil means the samples are invalid.
+func NIntegrateSamplesCumulative(x []float64,
+	y []float64) (cumulative []float64) {
+	if !validSamples(x, y) {
+		return nil
+	}
+	cumulative = make([]float64, len(x))
+	for i := 1; i < len(x); i++ {
+		cumulative[i] = cumulative[i-1] + (x[i]-x[i-1])*(y[i]+y[i-1])/2
+	}
+	return
+}
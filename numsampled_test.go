@@ -0,0 +1,55 @@
+// numsampled_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// Irregularly spaced samples of 3x^2 - x + 1 in [0, 2]
+func irregularSamples(count int) (x []float64, y []float64) {
+	x, y = make([]float64, count), make([]float64, count)
+	for i := range x {
+		u := float64(i) / float64(count-1)
+		x[i] = 2 * u * u
+		y[i] = 3*x[i]*x[i] - x[i] + 1
+	}
+	return
+}
+
+// Tests that Simpson's rule is exact for quadratics on irregular grids with
+// both even and odd numbers of intervals
+func TestSamplesSimpsonIrregular(t *testing.T) {
+	const exact float64 = 8
+	for _, count := range []int{3, 4, 7, 10} {
+		x, y := irregularSamples(count)
+		if result := NIntegrateSamplesSimpson(x, y); math.Abs(
+			result-exact) > 1e-12 {
+			t.Error(count, "samples produced", result, "instead of", exact)
+		}
+	}
+}
+
+// Tests that the trapezoid rule and the running integral agree and handle
+// irregular spacing
+func TestSamplesTrapezoidCumulative(t *testing.T) {
+	x := []float64{0, 0.1, 0.5, 1.5, 2}
+	y := []float64{1, 1.2, 2, 4, 5}
+	cumulative := NIntegrateSamplesCumulative(x, y)
+	expected := []float64{0, 0.11, 0.75, 3.75, 6}
+	for i := range expected {
+		if math.Abs(cumulative[i]-expected[i]) > 1e-14 {
+			t.Error("Running integral", cumulative, "instead of", expected)
+			break
+		}
+	}
+	if result := NIntegrateSamplesTrapezoid(x, y); result !=
+		cumulative[len(x)-1] {
+		t.Error("Trapezoid rule produced", result, "instead of",
+			cumulative[len(x)-1])
+	}
+	if !math.IsNaN(NIntegrateSamplesSimpson([]float64{0, 1, 1},
+		[]float64{0, 1, 2})) {
+		t.Error("Accepted repeated abscissae")
+	}
+}
@@ -98,9 +98,15 @@ func NSimpleSolveBisection(f0 SingleVarFunction, x0 float64, maxIterations int,
 	return math.NaN()
 }
 
+// Differentiates f at x with the fixed step of the solvers
+func fixedStepDerivative(f SingleVarFunction, x float64) float64 {
+	return NDifferentiateCentral(f, x, hsolve)
+}
+
 // The iteration function for the Newton method
-func newtonIteration(f CachedSingleVarFunction, xi float64) float64 {
-	deriv := NDifferentiateCentral(SingleVarFunction(f), xi, hsolve)
+func newtonIteration(f CachedSingleVarFunction, xi float64,
+	derivative NDifferentiator) float64 {
+	deriv := derivative(SingleVarFunction(f), xi)
 	if deriv == 0 {
 		return math.NaN()
 	}
@@ -115,6 +121,20 @@ func newtonIteration(f CachedSingleVarFunction, xi float64) float64 {
 // A `root` value of NaN means the function failed.
 func NSimpleSolveNewton(f0 SingleVarFunction, x0 float64, maxIterations int,
 	epsilon float64) (result float64) {
+	return newtonSolve(f0, x0, maxIterations, epsilon, fixedStepDerivative)
+}
+
+// NSimpleSolveNewtonAuto works the same way as NSimpleSolveNewton, except
+// that the derivatives are computed by NDifferentiateAuto instead of with a
+// fixed step, which suits functions of any scale.
+func NSimpleSolveNewtonAuto(f0 SingleVarFunction, x0 float64,
+	maxIterations int, epsilon float64) (result float64) {
+	return newtonSolve(f0, x0, maxIterations, epsilon, NDifferentiateAuto)
+}
+
+// The Newton method with the derivatives computed by derivative
+func newtonSolve(f0 SingleVarFunction, x0 float64, maxIterations int,
+	epsilon float64, derivative NDifferentiator) (result float64) {
 	var (
 		xi float64 = x0
 		fi float64
@@ -128,17 +148,17 @@ func NSimpleSolveNewton(f0 SingleVarFunction, x0 float64, maxIterations int,
 		if math.Abs(fi) < epsilon {
 			return xi
 		}
-		xi = newtonIteration(f, xi)
+		xi = newtonIteration(f, xi, derivative)
 	}
 	return math.NaN()
 }
 
 // Iteration function for the Halley method
 func halleyIteration(f CachedSingleVarFunction, fprime CachedSingleVarFunction,
-	xi float64) float64 {
+	xi float64, fsecond SingleVarFunction) float64 {
 	fi := f(xi)
 	f_di := fprime(xi)
-	f_d2i := NDifferentiateCentral(SingleVarFunction(fprime), xi, hsolve)
+	f_d2i := fsecond(xi)
 	factor := 2*f_di*f_di - fi*f_d2i
 	if factor == 0 {
 		return math.NaN()
@@ -154,12 +174,44 @@ func halleyIteration(f CachedSingleVarFunction, fprime CachedSingleVarFunction,
 // A `root` value of NaN means the function failed.
 func NSimpleSolveHalley(f0 SingleVarFunction, x0 float64, maxIterations int,
 	epsilon float64) (root float64) {
+	return halleySolve(f0, x0, maxIterations, epsilon, nil,
+		fixedStepDerivative, nil)
+}
+
+// NSimpleSolveHalleyAuto works the same way as NSimpleSolveHalley, except
+// that the derivatives are computed with automatically chosen steps instead
+// of a fixed one: the first by NDifferentiateAuto, and the second directly
+// from f by a second difference whose step is chosen the same way.
+func NSimpleSolveHalleyAuto(f0 SingleVarFunction, x0 float64,
+	maxIterations int, epsilon float64) (root float64) {
+	return halleySolve(f0, x0, maxIterations, epsilon, nil,
+		NDifferentiateAuto, autoSecondDerivative)
+}
+
+// The Halley method with the derivatives computed by derivative. The first
+// derivative is fprime0 instead if it is not nil. The second derivative is
+// computed from f by second if it is not nil, or else by applying
+// derivative to the first derivative.
+func halleySolve(f0 SingleVarFunction, x0 float64, maxIterations int,
+	epsilon float64, fprime0 SingleVarFunction, derivative NDifferentiator,
+	second NDifferentiator) (root float64) {
 	var (
 		f  CachedSingleVarFunction = CacheFunction(f0)
 		xi float64                 = x0
 		fi float64
 	)
-	fderiv := CacheFunction(NDerivative(SingleVarFunction(f), hsolve))
+	if fprime0 == nil {
+		fprime0 = func(x float64) float64 {
+			return derivative(SingleVarFunction(f), x)
+		}
+	}
+	fderiv := CacheFunction(fprime0)
+	fsecond := func(x float64) float64 {
+		if second != nil {
+			return second(SingleVarFunction(f), x)
+		}
+		return derivative(SingleVarFunction(fderiv), x)
+	}
 	for i := 0; i < maxIterations || maxIterations == 0; i++ {
 		if math.IsNaN(xi) {
 			return xi
@@ -168,11 +220,50 @@ func NSimpleSolveHalley(f0 SingleVarFunction, x0 float64, maxIterations int,
 		if math.Abs(fi) < epsilon {
 			return xi
 		}
-		xi = halleyIteration(f, fderiv, xi)
+		xi = halleyIteration(f, fderiv, xi, fsecond)
 	}
 	return math.NaN()
 }
 
+// Returns the real function whose complex extension is f, and its
+// derivative computed by NDifferentiateComplexStep.
+func complexStepFunctions(f ComplexFunction) (freal SingleVarFunction,
+	fprime SingleVarFunction) {
+	freal = func(x float64) float64 {
+		return real(f(complex(x, 0)))
+	}
+	fprime = func(x float64) float64 {
+		return NDifferentiateComplexStep(f, x, 0)
+	}
+	return
+}
+
+// NSimpleSolveNewtonComplexStep works the same way as NSimpleSolveNewton,
+// except that it takes the complex extension of the function (see
+// NDifferentiateComplexStep) and computes the derivatives by the complex
+// step, which makes them exact to the float64 precision. The root is
+// searched for on the real axis.
+func NSimpleSolveNewtonComplexStep(f ComplexFunction, x0 float64,
+	maxIterations int, epsilon float64) (root float64) {
+	freal, fprime := complexStepFunctions(f)
+	// The derivative does not need the real function it is given
+	derivative := func(_ SingleVarFunction, x float64) float64 {
+		return fprime(x)
+	}
+	return newtonSolve(freal, x0, maxIterations, epsilon, derivative)
+}
+
+// NSimpleSolveHalleyComplexStep works the same way as NSimpleSolveHalley,
+// except that it takes the complex extension of the function. The first
+// derivative is computed by the complex step and the second one by
+// NDifferentiateAuto from the first.
+func NSimpleSolveHalleyComplexStep(f ComplexFunction, x0 float64,
+	maxIterations int, epsilon float64) (root float64) {
+	freal, fprime := complexStepFunctions(f)
+	return halleySolve(freal, x0, maxIterations, epsilon, fprime,
+		NDifferentiateAuto, nil)
+}
+
 // Iteration function for the secant method
 func secantIteration(f CachedSingleVarFunction, xi float64,
 	xi_1 float64) float64 {
@@ -240,13 +331,14 @@ func genericSolveInnerAliasWithBisection(f CachedSingleVarFunction,
 	switch level {
 	case greedMedium:
 		xmid := 0.5 * (x0 + x1)
-		return newtonIteration(f, xmid)
+		return newtonIteration(f, xmid, fixedStepDerivative)
 	case greedHigh:
 		xmid := 0.5 * (x0 + x1)
 		return secantIteration(f, xmid, xmid+hsolve)
 	case greedHighest:
 		xmid := 0.5 * (x0 + x1)
-		return halleyIteration(f, fprime, xmid)
+		return halleyIteration(f, fprime, xmid, NDerivative(
+			SingleVarFunction(fprime), hsolve))
 	case greedLowest:
 		l, r := bisectionIteration(f, x0, x1)
 		if l != x0 {
@@ -266,11 +358,12 @@ func genericSolveInnerAlias(f CachedSingleVarFunction,
 	level solverGreed) (root float64) {
 	switch level {
 	case greedMedium:
-		return newtonIteration(f, xi)
+		return newtonIteration(f, xi, fixedStepDerivative)
 	case greedHigh:
 		return secantIteration(f, xi, xi+hsolve)
 	case greedHighest:
-		return halleyIteration(f, fprime, xi)
+		return halleyIteration(f, fprime, xi, NDerivative(
+			SingleVarFunction(fprime), hsolve))
 	default:
 		//panic(strconv.Itoa(int(level)) + strconv.Itoa(int(greedHighest)))
 		panic("Wrong argument at genericSolveInnerAlias")
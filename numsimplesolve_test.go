@@ -4,6 +4,7 @@ package gonumeth
 import (
 	//"fmt"
 	"math"
+	"math/cmplx"
 	"reflect"
 	"runtime"
 	"testing"
@@ -60,6 +61,8 @@ var solvers = []NSimpleSolver{
 	NSimpleSolveNewton,
 	NSimpleSolveSecant,
 	NSimpleSolveGeneric,
+	NSimpleSolveNewtonAuto,
+	NSimpleSolveHalleyAuto,
 }
 
 // Tests all solvers with the testing functions
@@ -94,3 +97,37 @@ func TestGracefulFailureTable(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the solvers with automatic steps handle a function whose
+// scale is too small for the fixed step
+func TestAutoStepSolvers(t *testing.T) {
+	f := func(x float64) float64 { return math.Sin(1000 * x) }
+	for _, solver := range []NSimpleSolver{NSimpleSolveNewtonAuto,
+		NSimpleSolveHalleyAuto} {
+		result := solver(f, 0.003, testiterations, 1e-12)
+		if math.Abs(result-math.Pi/1000) > 1e-14 {
+			t.Error("Method ", getFunctionName(solver), "produced ", result,
+				" instead of ", math.Pi/1000)
+		}
+	}
+}
+
+// Tests the solvers with complex-step derivatives
+func TestComplexStepSolvers(t *testing.T) {
+	f := func(z complex128) complex128 { return cmplx.Exp(z) - 2 }
+	for _, solver := range []func(ComplexFunction, float64, int,
+		float64) float64{NSimpleSolveNewtonComplexStep,
+		NSimpleSolveHalleyComplexStep} {
+		result := solver(f, 3, testiterations, 1e-14)
+		if math.Abs(result-math.Ln2) > 1e-14 {
+			t.Error("Method ", getFunctionName(solver), "produced ", result,
+				" instead of ", math.Ln2)
+		}
+	}
+	// Without a real root both fail gracefully
+	g := func(z complex128) complex128 { return z*z + 1 }
+	if result := NSimpleSolveNewtonComplexStep(g, 1, testiterations,
+		testepsilon); !math.IsNaN(result) {
+		t.Error("Produced a root", result, "for a positive function")
+	}
+}
@@ -0,0 +1,339 @@
+package gonumeth
+
+import (
+	"container/heap"
+	"github.com/skelterjohn/go.matrix"
+	"math"
+)
+
+// Index of the Grundmann-Moller rule used by the adaptive simplex
+// integration. The rule of index s has degree 2s+1, and the rule of index
+// s-1 is embedded in it for the error estimate.
+const simplexAdaptiveIndex int = 3
+
+// A simplex of an adaptive integration together with its partial result,
+// error estimate and the number of bisections that produced it.
+type simplexPiece struct {
+	vertices      [][]float64
+	result        float64
+	errorEstimate float64
+	depth         int
+}
+
+// A max-heap of simplices ordered by their error estimates.
+// It implements heap.Interface.
+type simplexHeap []simplexPiece
+
+func (h simplexHeap) Len() int { return len(h) }
+
+func (h simplexHeap) Less(i, j int) bool {
+	return h[i].errorEstimate > h[j].errorEstimate
+}
+
+func (h simplexHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *simplexHeap) Push(x interface{}) {
+	*h = append(*h, x.(simplexPiece))
+}
+
+func (h *simplexHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Sums the results and error estimates of all simplices.
+func (h simplexHeap) totals() (result float64, errorEstimate float64) {
+	for _, piece := range h {
+		result += piece.result
+		errorEstimate += piece.errorEstimate
+	}
+	return
+}
+
+// Checks that vertices hold the n+1 vertices of an n-dimensional simplex and
+// returns n, or 0 if they do not.
+func simplexDimension(vertices [][]float64) int {
+	n := len(vertices) - 1
+	if n < 1 {
+		return 0
+	}
+	for _, v := range vertices {
+		if len(v) != n {
+			return 0
+		}
+	}
+	return n
+}
+
+// Calculates the volume of a simplex as |det(v1-v0, ..., vn-v0)| / n!
+func simplexVolume(vertices [][]float64) float64 {
+	n := len(vertices) - 1
+	edges := matrix.Zeros(n, n)
+	factorial := 1.0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			edges.Set(i, j, vertices[i+1][j]-vertices[0][j])
+		}
+		factorial *= float64(i + 1)
+	}
+	return math.Abs(edges.Det()) / factorial
+}
+
+// Calls visit for every beta of n+1 non-negative integers summing to m
+func simplexCompositions(n int, m int, visit func(beta []int)) {
+	beta := make([]int, n+1)
+	var fill func(i int, rest int)
+	fill = func(i int, rest int) {
+		if i == n {
+			beta[n] = rest
+			visit(beta)
+			return
+		}
+		for k := 0; k <= rest; k++ {
+			beta[i] = k
+			fill(i+1, rest-k)
+		}
+	}
+	fill(0, m)
+}
+
+// Computes the sums S_m, m = 0..s, of f over the Grundmann-Moller points
+// with barycentric coordinates (2*beta_j + 1) / (2m + 1 + n), |beta| = m.
+// The rules of all indices up to s are combinations of these sums.
+func grundmannMollerSums(f MultiVarScalarFunction, vertices [][]float64,
+	s int) (sums []float64, evaluations int) {
+	n := len(vertices) - 1
+	sums = make([]float64, s+1)
+	point := make([]float64, n)
+	for m := 0; m <= s; m++ {
+		denominator := float64(2*m + 1 + n)
+		simplexCompositions(n, m, func(beta []int) {
+			for k := range point {
+				point[k] = 0
+			}
+			for j, v := range vertices {
+				c := float64(2*beta[j]+1) / denominator
+				for k := range point {
+					point[k] += c * v[k]
+				}
+			}
+			sums[m] += f(point)
+			evaluations++
+		})
+	}
+	return
+}
+
+// Combines the sums of grundmannMollerSums into the rule of index s on a
+// simplex of dimension n and the given volume
+func grundmannMollerRule(sums []float64, s int, n int,
+	volume float64) (result float64) {
+	d := 2*s + 1
+	for i := 0; i <= s; i++ {
+		lgI, _ := math.Lgamma(float64(i + 1))
+		lgDNI, _ := math.Lgamma(float64(d + n - i + 1))
+		weight := math.Exp(float64(d)*math.Log(float64(d+n-2*i)) - lgI -
+			lgDNI - float64(2*s)*math.Ln2)
+		if i%2 == 1 {
+			weight = -weight
+		}
+		result += weight * sums[s-i]
+	}
+	lgN, _ := math.Lgamma(float64(n + 1))
+	return result * volume * math.Exp(lgN)
+}
+
+// Integrates f over a simplex by the rules of index simplexAdaptiveIndex
+// and one below it, and stores the result and error estimate in piece.
+func grundmannMollerPiece(f MultiVarScalarFunction,
+	piece *simplexPiece) (evaluations int) {
+	n := len(piece.vertices) - 1
+	volume := simplexVolume(piece.vertices)
+	sums, evaluations := grundmannMollerSums(f, piece.vertices,
+		simplexAdaptiveIndex)
+	piece.result = grundmannMollerRule(sums, simplexAdaptiveIndex, n, volume)
+	lower := grundmannMollerRule(sums, simplexAdaptiveIndex-1, n, volume)
+	piece.errorEstimate = math.Abs(piece.result - lower)
+	return
+}
+
+// Returns the number of evaluations made by grundmannMollerPiece in n
+// dimensions: there are C(m+n, n) points with |beta| = m.
+func grundmannMollerPieceEvaluations(n int) (evaluations int) {
+	for m := 0; m <= simplexAdaptiveIndex; m++ {
+		points := 1
+		for k := 1; k <= n; k++ {
+			points = points * (m + k) / k
+		}
+		evaluations += points
+	}
+	return
+}
+
+// Splits a simplex in two by bisecting its longest edge, and returns the
+// length of the halves of that edge as width.
+func bisectSimplex(vertices [][]float64) (left [][]float64,
+	right [][]float64, width float64) {
+	var (
+		longest float64 = -1
+		vi, vj  int
+	)
+	for i := range vertices {
+		for j := i + 1; j < len(vertices); j++ {
+			length := 0.0
+			for k := range vertices[i] {
+				diff := vertices[i][k] - vertices[j][k]
+				length += diff * diff
+			}
+			if length > longest {
+				longest, vi, vj = length, i, j
+			}
+		}
+	}
+	mid := make([]float64, len(vertices[vi]))
+	for k := range mid {
+		mid[k] = (vertices[vi][k] + vertices[vj][k]) / 2
+	}
+	left = append([][]float64(nil), vertices...)
+	right = append([][]float64(nil), vertices...)
+	left[vi] = mid
+	right[vj] = mid
+	width = math.Sqrt(longest) / 2
+	return
+}
+
+// NIntegrateSimplexRule finds the numeric value of the integral of f over
+// the simplex with the given vertices by a Grundmann-Moller rule. An
+// n-dimensional simplex has n+1 vertices with n coordinates each, so a
+// triangle in the plane has 3 vertices and a tetrahedron 4. The rule
+// integrates polynomials of degree up to degree exactly (rounded up to an
+// odd number). Its weights are partly negative, so high degrees lose some
+// accuracy to cancellation.
+// A `result` value of NaN means the vertices are invalid or degree < 0.
+func NIntegrateSimplexRule(f MultiVarScalarFunction, vertices [][]float64,
+	degree int) (result float64) {
+	n := simplexDimension(vertices)
+	if n == 0 || degree < 0 {
+		return math.NaN()
+	}
+	s := degree / 2
+	sums, _ := grundmannMollerSums(f, vertices, s)
+	return grundmannMollerRule(sums, s, n, simplexVolume(vertices))
+}
+
+// NIntegrateSimplex attempts to find the numeric value of the integral of f
+// over the simplex with the given vertices adaptively. Each piece is
+// integrated by the degree 7 Grundmann-Moller rule, with the embedded
+// degree 5 rule as error estimate, and the piece with the largest error
+// estimate is repeatedly split in two across its longest edge. This goes on
+// until the error is within both targets (goalErrorAbs and
+// goalErrorRel * |result|) or until maxEvaluations function evaluations
+// have been spent. A value of 0 for maxEvaluations means there is no limit.
+// A `result` value of NaN means the vertices are invalid.
+// The function returns the result and error estimation as result and
+// errorEstimate respectively.
+func NIntegrateSimplex(f MultiVarScalarFunction, vertices [][]float64,
+	goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result float64, errorEstimate float64) {
+	detailed := NIntegrateSimplexDetailed(f, vertices, goalErrorAbs,
+		goalErrorRel, &IntegrationOptions{MaxEvaluations: maxEvaluations})
+	return detailed.Value, detailed.ErrorEstimate
+}
+
+// NIntegrateSimplexDetailed works the same way as NIntegrateSimplex, but
+// returns an IntegrationResult and accepts options. The bisection stops as
+// soon as splitting the worst piece would break a limit, with the status of
+// that limit; by default there are no limits. MaxDepth counts the
+// bisections of a piece and MinWidth is compared with the halves of the
+// bisected edge. A piece too small to be split is reported as
+// IntegrationRoundoff. If the first piece does not fit into
+// MaxEvaluations, nothing is evaluated and a NaN value is returned with an
+// infinite error estimate and IntegrationMaxEvaluations. Triangles and
+// tetrahedra are passed as their 3 or 4 vertices.
+// A Value of NaN with IntegrationDivergent means the vertices are invalid.
+func NIntegrateSimplexDetailed(f MultiVarScalarFunction,
+	vertices [][]float64, goalErrorAbs float64, goalErrorRel float64,
+	options *IntegrationOptions) (detailed IntegrationResult) {
+	if simplexDimension(vertices) == 0 {
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.NaN()
+		detailed.Status = IntegrationDivergent
+		return
+	}
+	limits := options.withDefaults(0, 0)
+	initial := simplexPiece{vertices: vertices}
+	perPiece := grundmannMollerPieceEvaluations(len(vertices) - 1)
+	if ok, status := limits.allow(0, perPiece, 0, math.Inf(1)); !ok {
+		// Not even the first piece fits into the budget
+		detailed.Value, detailed.ErrorEstimate = math.NaN(), math.Inf(1)
+		detailed.Status = status
+		return
+	}
+	grundmannMollerPiece(f, &initial)
+	var (
+		result        float64      = initial.result
+		errorEstimate float64      = initial.errorEstimate
+		pieces        *simplexHeap = &simplexHeap{initial}
+		status        IntegrationStatus
+	)
+	detailed.Evaluations = perPiece
+	for {
+		if integrationGoalsMet(result, errorEstimate, goalErrorAbs,
+			goalErrorRel) {
+			break
+		}
+		if !isFinite(result) || !isFinite(errorEstimate) {
+			status = IntegrationDivergent
+			break
+		}
+		worst := heap.Pop(pieces).(simplexPiece)
+		left, right, width := bisectSimplex(worst.vertices)
+		if simplexVolume(left) == 0 || simplexVolume(right) == 0 {
+			// The piece can not be split any further
+			heap.Push(pieces, worst)
+			status = IntegrationRoundoff
+			break
+		}
+		var ok bool
+		if ok, status = limits.allow(detailed.Evaluations, 2*perPiece,
+			worst.depth+1, width); !ok {
+			heap.Push(pieces, worst)
+			break
+		}
+		for _, vertices := range [2][][]float64{left, right} {
+			child := simplexPiece{vertices: vertices, depth: worst.depth + 1}
+			detailed.Evaluations += grundmannMollerPiece(f, &child)
+			heap.Push(pieces, child)
+			result += child.result
+			errorEstimate += child.errorEstimate
+		}
+		result -= worst.result
+		errorEstimate -= worst.errorEstimate
+	}
+	// Summing afresh avoids the rounding accumulated by the updates above
+	detailed.Value, detailed.ErrorEstimate = pieces.totals()
+	detailed.Subintervals = pieces.Len()
+	detailed.Status = status
+	return
+}
+
+// NIntegrateTriangle works the same way as NIntegrateSimplex for the
+// triangle with vertices v0, v1 and v2 in the plane.
+func NIntegrateTriangle(f MultiVarScalarFunction, v0 []float64, v1 []float64,
+	v2 []float64, goalErrorAbs float64, goalErrorRel float64,
+	maxEvaluations int) (result float64, errorEstimate float64) {
+	return NIntegrateSimplex(f, [][]float64{v0, v1, v2}, goalErrorAbs,
+		goalErrorRel, maxEvaluations)
+}
+
+// NIntegrateTetrahedron works the same way as NIntegrateSimplex for the
+// tetrahedron with vertices v0, v1, v2 and v3 in space.
+func NIntegrateTetrahedron(f MultiVarScalarFunction, v0 []float64,
+	v1 []float64, v2 []float64, v3 []float64, goalErrorAbs float64,
+	goalErrorRel float64, maxEvaluations int) (result float64,
+	errorEstimate float64) {
+	return NIntegrateSimplex(f, [][]float64{v0, v1, v2, v3}, goalErrorAbs,
+		goalErrorRel, maxEvaluations)
+}
@@ -0,0 +1,75 @@
+// numsimplex_test.go
+package gonumeth
+
+import (
+	"math"
+	"testing"
+)
+
+// The unit triangle and tetrahedron
+var (
+	unitTriangle    = [][]float64{{0, 0}, {1, 0}, {0, 1}}
+	unitTetrahedron = [][]float64{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+)
+
+// Tests the degree of exactness of the Grundmann-Moller rules. The integral
+// of x^a y^b z^c over the unit tetrahedron is a! b! c! / (a+b+c+3)!.
+func TestSimplexRuleDegree(t *testing.T) {
+	for degree := 0; degree <= 7; degree++ {
+		f := func(x []float64) float64 { return math.Pow(x[0], float64(degree)) }
+		exact := 1 / float64((degree+1)*(degree+2))
+		if result := NIntegrateSimplexRule(f, unitTriangle, degree); math.Abs(
+			result-exact) > 1e-14 {
+			t.Error("Degree", degree, "produced", result, "instead of", exact)
+		}
+	}
+	f := func(x []float64) float64 { return x[0] * x[0] * x[1] * x[2] }
+	if result := NIntegrateSimplexRule(f, unitTetrahedron, 4); math.Abs(
+		result-2.0/5040) > 1e-15 {
+		t.Error("Produced", result, "instead of", 2.0/5040)
+	}
+}
+
+// Tests the adaptive integration with a corner singularity on a triangle
+// and a smooth integrand on a tetrahedron, and that the evaluation budget
+// is respected
+func TestSimplexAdaptive(t *testing.T) {
+	calls := 0
+	inverseRadius := func(x []float64) float64 {
+		calls++
+		return 1 / math.Hypot(x[0], x[1])
+	}
+	exact := math.Sqrt2 * math.Log(1+math.Sqrt2)
+	result, errorEstimate := NIntegrateTriangle(inverseRadius,
+		unitTriangle[0], unitTriangle[1], unitTriangle[2], 1e-8, 1e-8, 0)
+	if math.Abs(result-exact) > 1e-8*exact {
+		t.Error("Triangle produced", result, "instead of", exact)
+	}
+	if math.Abs(result-exact) > errorEstimate || errorEstimate > 1e-8*exact {
+		t.Error("Triangle has error estimate", errorEstimate,
+			"for the actual error", math.Abs(result-exact))
+	}
+	for _, budget := range []int{100, 1000} {
+		calls = 0
+		NIntegrateTriangle(inverseRadius, unitTriangle[0], unitTriangle[1],
+			unitTriangle[2], 0, 0, budget)
+		if calls > budget {
+			t.Error("Used", calls, "evaluations with a budget of", budget)
+		}
+	}
+	exponential := func(x []float64) float64 {
+		return math.Exp(x[0] + x[1] + x[2])
+	}
+	exact = math.E/2 - 1
+	result, errorEstimate = NIntegrateTetrahedron(exponential,
+		unitTetrahedron[0], unitTetrahedron[1], unitTetrahedron[2],
+		unitTetrahedron[3], 1e-10, 1e-10, 0)
+	if math.Abs(result-exact) > 1e-10 {
+		t.Error("Tetrahedron produced", result, "with error estimate",
+			errorEstimate, "instead of", exact)
+	}
+	if result, _ := NIntegrateSimplex(exponential, [][]float64{{0, 0},
+		{1, 0}}, 1, 1, 0); !math.IsNaN(result) {
+		t.Error("Accepted vertices of the wrong dimension")
+	}
+}
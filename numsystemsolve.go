@@ -68,9 +68,14 @@ func NSolveSystemFixedPoint(f MultiVarFunction, x0 matrix.Matrix,
 	return nil
 }
 
+// Differentiates f at x with the fixed step of the system solvers
+func fixedStepSystemDerivative(f SingleVarFunction, x float64) float64 {
+	return NDifferentiateCentral(f, x, hf)
+}
+
 // Calculates the Jacobian matrix of f at x0.
-func jacobianOfSystem(f MultiVarFunction,
-	x0 matrix.Matrix) (result matrix.Matrix) {
+func jacobianOfSystem(f MultiVarFunction, x0 matrix.Matrix,
+	derivative NDifferentiator) (result matrix.Matrix) {
 	n := x0.Cols()
 	result = matrix.Zeros(n, n)
 	for i := 0; i < n; i++ {
@@ -82,7 +87,7 @@ func jacobianOfSystem(f MultiVarFunction,
 					matrix.Scaled(delta, x)))
 				return interimResult.Get(0, j)
 			}
-			deriv := NDifferentiateCentral(lambda, 0, hf)
+			deriv := derivative(lambda, 0)
 			result.Set(i, j, deriv)
 		}
 	}
@@ -96,6 +101,24 @@ func jacobianOfSystem(f MultiVarFunction,
 // failed.
 func NSolveSystemNewton(f MultiVarFunction, x0 matrix.Matrix, maxIterations int,
 	epsilon float64) (root matrix.Matrix) {
+	return systemNewtonSolve(f, x0, maxIterations, epsilon,
+		fixedStepSystemDerivative)
+}
+
+// NSolveSystemNewtonAuto works the same way as NSolveSystemNewton, except
+// that the partial derivatives of the Jacobian are computed by
+// NDifferentiateAuto instead of with a fixed step.
+func NSolveSystemNewtonAuto(f MultiVarFunction, x0 matrix.Matrix,
+	maxIterations int, epsilon float64) (root matrix.Matrix) {
+	return systemNewtonSolve(f, x0, maxIterations, epsilon,
+		NDifferentiateAuto)
+}
+
+// The Newton's method for systems with the partial derivatives computed by
+// derivative
+func systemNewtonSolve(f MultiVarFunction, x0 matrix.Matrix,
+	maxIterations int, epsilon float64,
+	derivative NDifferentiator) (root matrix.Matrix) {
 	var (
 		xi    matrix.Matrix = x0
 		fi    matrix.Matrix
@@ -110,7 +133,7 @@ func NSolveSystemNewton(f MultiVarFunction, x0 matrix.Matrix, maxIterations int,
 		if matrixIsZero(fi, epsilon) {
 			return xi
 		}
-		Ji = jacobianOfSystem(f, xi)
+		Ji = jacobianOfSystem(f, xi, derivative)
 		if Ji.Det() == 0 {
 			return nil
 		}
@@ -122,7 +145,8 @@ func NSolveSystemNewton(f MultiVarFunction, x0 matrix.Matrix, maxIterations int,
 }
 
 // Calculates n derivatives (df_i / dx_i) of f at x0
-func simpleDerivs(f MultiVarFunction, x0 matrix.Matrix) (result matrix.Matrix) {
+func simpleDerivs(f MultiVarFunction, x0 matrix.Matrix,
+	derivative NDifferentiator) (result matrix.Matrix) {
 	result = matrix.MakeDenseCopy(x0)
 	for i := 0; i < result.Cols(); i++ {
 		delta := matrix.Zeros(result.Rows(), result.Cols())
@@ -132,7 +156,7 @@ func simpleDerivs(f MultiVarFunction, x0 matrix.Matrix) (result matrix.Matrix) {
 				matrix.Scaled(delta, x)))
 			return interimResult.Get(0, i)
 		}
-		deriv := NDifferentiateCentral(lambda, 0, hf)
+		deriv := derivative(lambda, 0)
 		result.Set(0, i, deriv)
 	}
 	return
@@ -146,6 +170,23 @@ func simpleDerivs(f MultiVarFunction, x0 matrix.Matrix) (result matrix.Matrix) {
 // failed.
 func NSolveSystemDeriv(f MultiVarFunction, x0 matrix.Matrix, maxIterations int,
 	epsilon float64) (root matrix.Matrix) {
+	return systemDerivSolve(f, x0, maxIterations, epsilon,
+		fixedStepSystemDerivative)
+}
+
+// NSolveSystemDerivAuto works the same way as NSolveSystemDeriv, except
+// that the derivatives are computed by NDifferentiateAuto instead of with a
+// fixed step.
+func NSolveSystemDerivAuto(f MultiVarFunction, x0 matrix.Matrix,
+	maxIterations int, epsilon float64) (root matrix.Matrix) {
+	return systemDerivSolve(f, x0, maxIterations, epsilon, NDifferentiateAuto)
+}
+
+// The derivative-based solver for systems with the derivatives computed by
+// derivative
+func systemDerivSolve(f MultiVarFunction, x0 matrix.Matrix,
+	maxIterations int, epsilon float64,
+	derivative NDifferentiator) (root matrix.Matrix) {
 	var (
 		xi     matrix.Matrix = x0
 		fi     matrix.Matrix
@@ -159,7 +200,7 @@ func NSolveSystemDeriv(f MultiVarFunction, x0 matrix.Matrix, maxIterations int,
 		if matrixIsZero(fi, epsilon) {
 			return xi
 		}
-		derivs = simpleDerivs(f, xi)
+		derivs = simpleDerivs(f, xi, derivative)
 		for j := 0; j < xi.Rows(); j++ {
 			for k := 0; k < xi.Cols(); k++ {
 				newVal := xi.Get(j, k) - fi.Get(j, k)/derivs.Get(j, k)